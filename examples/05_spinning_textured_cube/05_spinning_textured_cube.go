@@ -2,10 +2,10 @@ package main
 
 import (
 	"fmt"
-	"math"
     "time"
 
 	"github.com/RostislavArts/quickcgo/quickcg"
+	"github.com/RostislavArts/quickcgo/vecmath"
 )
 
 const (
@@ -20,40 +20,17 @@ var (
 	distanceFromCam int = 150
 	incrementSpeed  float64 = 0.5
 
-    sinA, cosA float64 
-    sinB, cosB float64 
-    sinC, cosC float64 
+    rotation vecmath.Mat4
 
     texture []quickcg.ColorRGB
     texW, texH int
 )
 
-func calculateX(i, j, k float64) float64 {
-	return j * sinA * sinB * cosC -
-		k * cosA * sinB * cosC +
-		j * cosA * sinC +
-		k * sinA * sinC +
-		i * cosB * cosC
-}
-
-func calculateY(i, j, k float64) float64 {
-	return j * cosA * cosC +
-		k * sinA * cosC -
-		j * sinA * sinB * sinC +
-		k * cosA * sinB * sinC -
-		i * cosB * sinC
-}
-
-func calculateZ(i, j, k float64) float64 {
-	return k * cosA * cosB -
-		j * sinA * cosB +
-		i * sinB
-}
-
 func calculateForSurface(cubeX, cubeY, cubeZ float64, zBuffer *[renderW * renderH]float64, buffer *[renderW * renderH]quickcg.ColorRGB, tex []quickcg.ColorRGB, texW, texH int,texUCoord, texVCoord float64) {
-	x := calculateX(cubeX, cubeY, cubeZ)
-	y := calculateY(cubeX, cubeY, cubeZ)
-	z := calculateZ(cubeX, cubeY, cubeZ) + float64(distanceFromCam)
+	p := rotation.MulDir(vecmath.Vec3{X: cubeX, Y: cubeY, Z: cubeZ})
+	x := p.X
+	y := p.Y
+	z := p.Z + float64(distanceFromCam)
 
 	if z <= 0 {
 		return
@@ -123,9 +100,7 @@ func main() {
 			buffer[i] = quickcg.ColorRGB{R: 0, G: 0, B: 0}
 		}
 
-        sinA, cosA = math.Sin(a), math.Cos(a)
-        sinB, cosB = math.Sin(b), math.Cos(b)
-        sinC, cosC = math.Sin(c), math.Cos(c)
+        rotation = vecmath.RotateZ(-c).Mul(vecmath.RotateY(-b)).Mul(vecmath.RotateX(-a))
 
 		for cubeX := -cubeWidth; cubeX < cubeWidth; cubeX += incrementSpeed {
 			for cubeY := -cubeWidth; cubeY < cubeWidth; cubeY += incrementSpeed {
@@ -19,6 +19,7 @@ func main() {
 	offsetX := 0.0
 	offsetY := 0.0
 	maxIter := 128
+	palette := quickcg.Inferno()
 
 	rendered := false
 	for !quickcg.Done(64) {
@@ -45,12 +46,7 @@ func main() {
 
 					if i < maxIter {
 						t := float64(i) / float64(maxIter)
-						r := uint8(9 * (1 - t) * t * t * t * 255)
-						g := uint8(15 * (1 - t) * (1 - t) * t * t * 255)
-						b := uint8(8.5 * (1 - t) * (1 - t) * (1 - t) * t * 255)
-
-						color := quickcg.ColorRGB{R: r, G: g, B: b}
-						s.PSet(x, y, color)
+						s.PSet(x, y, palette.At(t))
 					}
 				}
 			}
@@ -0,0 +1,145 @@
+package filter
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+)
+
+// BoxBlur replaces every pixel with the average of the (2*radius+1)²
+// pixels around it.
+func BoxBlur(img *quickcg.Image, radius int, edge EdgeMode) {
+	if radius <= 0 {
+		return
+	}
+	k := make([]float64, 2*radius+1)
+	for i := range k {
+		k[i] = 1 / float64(len(k))
+	}
+	ConvolveSeparable(img, k, k, edge)
+}
+
+// GaussianKernel returns the normalized one-dimensional Gaussian of standard
+// deviation sigma, cut off at three standard deviations.
+func GaussianKernel(sigma float64) []float64 {
+	radius := int(math.Ceil(3 * sigma))
+	k := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range k {
+		x := float64(i - radius)
+		k[i] = math.Exp(-x * x / (2 * sigma * sigma))
+		sum += k[i]
+	}
+	for i := range k {
+		k[i] /= sum
+	}
+	return k
+}
+
+// GaussianBlur blurs img with a Gaussian of standard deviation sigma pixels.
+func GaussianBlur(img *quickcg.Image, sigma float64, edge EdgeMode) {
+	if sigma <= 0 {
+		return
+	}
+	k := GaussianKernel(sigma)
+	ConvolveSeparable(img, k, k, edge)
+}
+
+// Sharpen strengthens the difference between every pixel and its four
+// neighbours.
+func Sharpen(img *quickcg.Image, edge EdgeMode) {
+	Convolve(img, SharpenKernel, edge)
+}
+
+// Emboss turns img into a gray relief lit from the top left, with colored
+// fringes where the channels differ.
+func Emboss(img *quickcg.Image, edge EdgeMode) {
+	Convolve(img, EmbossKernel, edge)
+}
+
+// UnsharpMask sharpens img by adding amount times the difference between it
+// and a Gaussian blur of standard deviation sigma. Differences of threshold
+// or less, in the 0-255 channel range, are left alone so that noise in flat
+// areas is not amplified.
+func UnsharpMask(img *quickcg.Image, sigma, amount float64, threshold int, edge EdgeMode) {
+	if sigma <= 0 {
+		return
+	}
+	src := toPlanes(img)
+	k := GaussianKernel(sigma)
+	blurred := src.separable(k, k, edge)
+
+	for ch, orig := range src.c {
+		for i, v := range orig {
+			diff := v - blurred.c[ch][i]
+			if math.Abs(diff) > float64(threshold) {
+				orig[i] = v + amount*diff
+			}
+		}
+	}
+	src.store(img)
+}
+
+// Median replaces every channel of every pixel with the median of that
+// channel over the (2*radius+1)² pixels around it. It removes salt-and-pepper
+// noise while keeping edges sharp. Each row slides a histogram across the
+// image (Huang's algorithm), so the cost grows linearly with the radius.
+func Median(img *quickcg.Image, radius int, edge EdgeMode) {
+	if radius <= 0 {
+		return
+	}
+	xs := edgeMap(img.W, -radius, radius, edge)
+	ys := edgeMap(img.H, -radius, radius, edge)
+	size := 2*radius + 1
+	half := size * size / 2
+
+	src := img.Clone()
+	value := func(x, y, ch int) uint8 {
+		if x < 0 || y < 0 {
+			return 0
+		}
+		c := src.Pixels[y*src.W+x]
+		switch ch {
+		case 0:
+			return c.R
+		case 1:
+			return c.G
+		}
+		return c.B
+	}
+
+	out := make([][3]uint8, len(img.Pixels))
+	for ch := range 3 {
+		for y := range img.H {
+			var hist [256]int
+			for _, row := range ys {
+				for _, col := range xs {
+					hist[value(col[0], row[y], ch)]++
+				}
+			}
+
+			for x := range img.W {
+				if x > 0 {
+					// Slide the window one pixel to the right.
+					for _, row := range ys {
+						hist[value(xs[0][x-1], row[y], ch)]--
+						hist[value(xs[size-1][x], row[y], ch)]++
+					}
+				}
+
+				acc, v := 0, 0
+				for v = range hist {
+					acc += hist[v]
+					if acc > half {
+						break
+					}
+				}
+				out[y*img.W+x][ch] = uint8(v)
+			}
+		}
+	}
+
+	for i, c := range out {
+		img.Pixels[i] = quickcg.ColorRGB{R: c[0], G: c[1], B: c[2]}
+	}
+}
@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+)
+
+// Sobel replaces every pixel with the gradient magnitude of each channel
+// under the Sobel operator, so edges light up on a black background.
+// Convert the image to grayscale first for classic single-channel edges.
+func Sobel(img *quickcg.Image, edge EdgeMode) {
+	gradient(img, SobelX, SobelY, edge)
+}
+
+// Prewitt is like Sobel but weights all three rows of the neighbourhood
+// equally, which responds slightly more to noise.
+func Prewitt(img *quickcg.Image, edge EdgeMode) {
+	gradient(img, PrewittX, PrewittY, edge)
+}
+
+// Laplacian replaces every pixel with the absolute value of the Laplacian of
+// each channel, which marks edges from all directions at once with thin lines.
+func Laplacian(img *quickcg.Image, edge EdgeMode) {
+	out := toPlanes(img).convolve(LaplacianKernel, edge)
+	for _, c := range out.c {
+		for i, v := range c {
+			c[i] = math.Abs(v)
+		}
+	}
+	out.store(img)
+}
+
+// gradient stores the magnitude of the horizontal and vertical responses kx and ky.
+func gradient(img *quickcg.Image, kx, ky Kernel, edge EdgeMode) {
+	src := toPlanes(img)
+	gx := src.convolve(kx, edge)
+	gy := src.convolve(ky, edge)
+	for ch, c := range gx.c {
+		for i, v := range c {
+			c[i] = math.Hypot(v, gy.c[ch][i])
+		}
+	}
+	gx.store(img)
+}
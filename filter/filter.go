@@ -0,0 +1,243 @@
+// Package filter implements classic image filters on quickcg images:
+// convolution with arbitrary kernels, blurs, sharpening, embossing, edge
+// detection and the median filter.
+//
+// Every filter works in place. To filter what is on screen, pass the screen
+// buffer as an image:
+//
+//	filter.GaussianBlur(screen.BufferImage(), 2, filter.EdgeClamp)
+//	screen.DrawBuffer()
+package filter
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+)
+
+// EdgeMode selects which pixels a filter reads beyond the image border.
+type EdgeMode int
+
+const (
+	// EdgeClamp repeats the nearest border pixel.
+	EdgeClamp EdgeMode = iota
+	// EdgeWrap continues from the opposite border, for tileable images.
+	EdgeWrap
+	// EdgeMirror reflects the image at the border, repeating the border pixel.
+	EdgeMirror
+	// EdgeZero treats everything outside the image as black.
+	EdgeZero
+)
+
+// Kernel is a convolution matrix of W x H weights in row-major order,
+// centered on the element at (W/2, H/2). W and H must be odd. Offset is
+// added to every result, in the 0-255 range of the channels.
+type Kernel struct {
+	W, H   int
+	Values []float64
+	Offset float64
+}
+
+// NewKernel creates a w x h kernel from its weights in row-major order.
+func NewKernel(w, h int, values ...float64) Kernel {
+	return Kernel{W: w, H: h, Values: values}
+}
+
+// Validate reports whether the kernel has positive, odd dimensions and
+// exactly W*H weights.
+func (k Kernel) Validate() error {
+	if k.W <= 0 || k.H <= 0 || k.W%2 == 0 || k.H%2 == 0 {
+		return fmt.Errorf("filter: kernel size %dx%d is not odd and positive", k.W, k.H)
+	}
+	if len(k.Values) != k.W*k.H {
+		return fmt.Errorf("filter: %dx%d kernel has %d values, want %d", k.W, k.H, len(k.Values), k.W*k.H)
+	}
+	return nil
+}
+
+// Normalized returns a copy of the kernel scaled so that its weights sum to
+// one, which keeps the overall brightness of the image. Kernels whose weights
+// sum to zero are returned unchanged.
+func (k Kernel) Normalized() Kernel {
+	sum := 0.0
+	for _, v := range k.Values {
+		sum += v
+	}
+	if sum == 0 {
+		return k
+	}
+
+	values := make([]float64, len(k.Values))
+	for i, v := range k.Values {
+		values[i] = v / sum
+	}
+	return Kernel{W: k.W, H: k.H, Values: values, Offset: k.Offset}
+}
+
+// Common 3x3 kernels.
+var (
+	SharpenKernel = NewKernel(3, 3,
+		0, -1, 0,
+		-1, 5, -1,
+		0, -1, 0)
+	EmbossKernel = Kernel{W: 3, H: 3, Offset: 128, Values: []float64{
+		-1, -1, 0,
+		-1, 0, 1,
+		0, 1, 1,
+	}}
+	SobelX = NewKernel(3, 3,
+		-1, 0, 1,
+		-2, 0, 2,
+		-1, 0, 1)
+	SobelY = NewKernel(3, 3,
+		-1, -2, -1,
+		0, 0, 0,
+		1, 2, 1)
+	PrewittX = NewKernel(3, 3,
+		-1, 0, 1,
+		-1, 0, 1,
+		-1, 0, 1)
+	PrewittY = NewKernel(3, 3,
+		-1, -1, -1,
+		0, 0, 0,
+		1, 1, 1)
+	LaplacianKernel = NewKernel(3, 3,
+		0, 1, 0,
+		1, -4, 1,
+		0, 1, 0)
+)
+
+// Convolve replaces every pixel of img with the weighted sum of its
+// neighbourhood under k, clamped to the channel range. It panics if k is
+// not valid; see Validate.
+func Convolve(img *quickcg.Image, k Kernel, edge EdgeMode) {
+	if err := k.Validate(); err != nil {
+		panic(err)
+	}
+	toPlanes(img).convolve(k, edge).store(img)
+}
+
+// ConvolveSeparable convolves img with the horizontal kernel h and then with
+// the vertical kernel v. This equals Convolve with their outer product but
+// costs len(h)+len(v) instead of len(h)*len(v) operations per pixel.
+// It panics unless h and v have odd lengths.
+func ConvolveSeparable(img *quickcg.Image, h, v []float64, edge EdgeMode) {
+	for _, k := range []Kernel{{W: len(h), H: 1, Values: h}, {W: 1, H: len(v), Values: v}} {
+		if err := k.Validate(); err != nil {
+			panic(err)
+		}
+	}
+	toPlanes(img).separable(h, v, edge).store(img)
+}
+
+// planes holds the red, green and blue channels of an image as floats in
+// the 0-255 range, so that filter passes do not round in between.
+type planes struct {
+	w, h int
+	c    [3][]float64
+}
+
+func toPlanes(img *quickcg.Image) *planes {
+	p := newPlanes(img.W, img.H)
+	for i, c := range img.Pixels {
+		p.c[0][i] = float64(c.R)
+		p.c[1][i] = float64(c.G)
+		p.c[2][i] = float64(c.B)
+	}
+	return p
+}
+
+func newPlanes(w, h int) *planes {
+	p := &planes{w: w, h: h}
+	for ch := range p.c {
+		p.c[ch] = make([]float64, w*h)
+	}
+	return p
+}
+
+// store writes the planes back into img, rounding and clamping each channel.
+func (p *planes) store(img *quickcg.Image) {
+	for i := range img.Pixels {
+		img.Pixels[i] = quickcg.ColorRGB{
+			R: clampUint8(p.c[0][i]),
+			G: clampUint8(p.c[1][i]),
+			B: clampUint8(p.c[2][i]),
+		}
+	}
+}
+
+// convolve returns the planes convolved with k.
+func (p *planes) convolve(k Kernel, edge EdgeMode) *planes {
+	ax, ay := k.W/2, k.H/2
+	xs := edgeMap(p.w, -ax, k.W-1-ax, edge)
+	ys := edgeMap(p.h, -ay, k.H-1-ay, edge)
+
+	out := newPlanes(p.w, p.h)
+	for ch, src := range p.c {
+		dst := out.c[ch]
+		for y := range p.h {
+			for x := range p.w {
+				sum := k.Offset
+				for ky, row := range ys {
+					sy := row[y]
+					if sy < 0 {
+						continue
+					}
+					for kx, col := range xs {
+						if sx := col[x]; sx >= 0 {
+							sum += k.Values[ky*k.W+kx] * src[sy*p.w+sx]
+						}
+					}
+				}
+				dst[y*p.w+x] = sum
+			}
+		}
+	}
+	return out
+}
+
+// separable returns the planes convolved with h along rows and v along columns.
+func (p *planes) separable(h, v []float64, edge EdgeMode) *planes {
+	return p.convolve(Kernel{W: len(h), H: 1, Values: h}, edge).
+		convolve(Kernel{W: 1, H: len(v), Values: v}, edge)
+}
+
+// edgeMap returns, for every offset d in [lo, hi], the index read for each
+// position 0..n-1 shifted by d, or -1 where EdgeZero reads black.
+func edgeMap(n, lo, hi int, edge EdgeMode) [][]int {
+	m := make([][]int, hi-lo+1)
+	for d := lo; d <= hi; d++ {
+		row := make([]int, n)
+		for i := range row {
+			row[i] = edgeIndex(i+d, n, edge)
+		}
+		m[d-lo] = row
+	}
+	return m
+}
+
+func edgeIndex(i, n int, edge EdgeMode) int {
+	if i >= 0 && i < n {
+		return i
+	}
+	switch edge {
+	case EdgeWrap:
+		return (i%n + n) % n
+	case EdgeMirror:
+		period := 2 * n
+		i = (i%period + period) % period
+		if i >= n {
+			i = period - 1 - i
+		}
+		return i
+	case EdgeZero:
+		return -1
+	default:
+		return min(max(i, 0), n-1)
+	}
+}
+
+func clampUint8(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v))))
+}
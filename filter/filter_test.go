@@ -0,0 +1,136 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+)
+
+// grayImage creates an image from rows of gray levels.
+func grayImage(rows ...[]uint8) *quickcg.Image {
+	img := quickcg.NewImage(len(rows[0]), len(rows))
+	for y, row := range rows {
+		for x, v := range row {
+			img.Pixels[y*img.W+x] = quickcg.ColorRGB{R: v, G: v, B: v}
+		}
+	}
+	return img
+}
+
+// checkGray fails the test unless every pixel of img is the gray level in want.
+func checkGray(t *testing.T, img *quickcg.Image, want ...[]uint8) {
+	t.Helper()
+	for y, row := range want {
+		for x, v := range row {
+			if got := img.Pixels[y*img.W+x]; got != (quickcg.ColorRGB{R: v, G: v, B: v}) {
+				t.Errorf("pixel (%d, %d) = %v, want gray %d", x, y, got, v)
+			}
+		}
+	}
+}
+
+func TestEdgeModes(t *testing.T) {
+	// A kernel reading two pixels to the left shows what lies beyond the border.
+	k := NewKernel(5, 1, 1, 0, 0, 0, 0)
+	for _, tt := range []struct {
+		edge EdgeMode
+		want []uint8
+	}{
+		{EdgeClamp, []uint8{10, 10, 10}},
+		{EdgeWrap, []uint8{20, 30, 10}},
+		{EdgeMirror, []uint8{20, 10, 10}},
+		{EdgeZero, []uint8{0, 0, 10}},
+	} {
+		img := grayImage([]uint8{10, 20, 30})
+		Convolve(img, k, tt.edge)
+		checkGray(t, img, tt.want)
+	}
+}
+
+func TestBoxBlur(t *testing.T) {
+	img := grayImage(
+		[]uint8{0, 0, 0},
+		[]uint8{0, 90, 0},
+		[]uint8{0, 0, 0})
+	BoxBlur(img, 1, EdgeZero)
+	checkGray(t, img,
+		[]uint8{10, 10, 10},
+		[]uint8{10, 10, 10},
+		[]uint8{10, 10, 10})
+}
+
+func TestGaussianBlur(t *testing.T) {
+	k := GaussianKernel(1)
+	if len(k) != 7 {
+		t.Fatalf("len(GaussianKernel(1)) = %d, want 7", len(k))
+	}
+	sum := 0.0
+	for i, v := range k {
+		sum += v
+		if v != k[len(k)-1-i] {
+			t.Errorf("kernel is not symmetric: %v", k)
+		}
+	}
+	if sum < 1-1e-12 || sum > 1+1e-12 {
+		t.Errorf("kernel sums to %v, want 1", sum)
+	}
+
+	// Blurring keeps flat areas and spreads a step symmetrically.
+	img := grayImage([]uint8{0, 0, 0, 200, 200, 200})
+	GaussianBlur(img, 1, EdgeClamp)
+	got := img.Pixels
+	if got[0].R > 1 || got[5].R < 199 {
+		t.Errorf("flat ends changed: %v", got)
+	}
+	if int(got[2].R)+int(got[3].R) != 200 {
+		t.Errorf("step is not symmetric: %v", got)
+	}
+}
+
+func TestSobel(t *testing.T) {
+	img := grayImage(
+		[]uint8{0, 0, 10},
+		[]uint8{0, 0, 10},
+		[]uint8{0, 0, 10})
+	Sobel(img, EdgeClamp)
+	checkGray(t, img,
+		[]uint8{0, 40, 40},
+		[]uint8{0, 40, 40},
+		[]uint8{0, 40, 40})
+}
+
+func TestMedian(t *testing.T) {
+	img := grayImage(
+		[]uint8{10, 10, 10, 10},
+		[]uint8{10, 255, 10, 10},
+		[]uint8{10, 10, 0, 10})
+	Median(img, 1, EdgeClamp)
+	checkGray(t, img,
+		[]uint8{10, 10, 10, 10},
+		[]uint8{10, 10, 10, 10},
+		[]uint8{10, 10, 10, 10})
+}
+
+func TestKernelValidate(t *testing.T) {
+	for _, k := range []Kernel{
+		NewKernel(3, 3, 1, 2),
+		NewKernel(2, 1, 1, 1),
+		NewKernel(0, 0),
+		NewKernel(-1, 1, 1),
+	} {
+		if k.Validate() == nil {
+			t.Errorf("%+v is valid, want an error", k)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Convolve with %+v did not panic", k)
+				}
+			}()
+			Convolve(quickcg.NewImage(2, 2), k, EdgeClamp)
+		}()
+	}
+	if err := SobelX.Validate(); err != nil {
+		t.Errorf("SobelX: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package noise
+
+import (
+	"math"
+)
+
+// Fractal sums octaves of a noise function at rising frequencies and falling
+// amplitudes, which adds fine detail on top of the coarse shapes.
+type Fractal struct {
+	Octaves    int     // number of layers summed
+	Lacunarity float64 // frequency factor from one octave to the next
+	Gain       float64 // amplitude factor from one octave to the next
+}
+
+// DefaultFractal sums six octaves, each at twice the frequency and half the
+// amplitude of the previous one.
+var DefaultFractal = Fractal{Octaves: 6, Lacunarity: 2, Gain: 0.5}
+
+// sum adds up octave(freq) * amp over the octaves, applying shape to every
+// octave, and divides by the total amplitude so the result keeps the range
+// of a single octave.
+func (fr Fractal) sum(octave func(freq float64) float64, shape func(float64) float64) float64 {
+	total, norm := 0.0, 0.0
+	freq, amp := 1.0, 1.0
+	for range fr.Octaves {
+		total += shape(octave(freq)) * amp
+		norm += amp
+		freq *= fr.Lacunarity
+		amp *= fr.Gain
+	}
+	if norm == 0 {
+		return 0
+	}
+	return total / norm
+}
+
+func identity(v float64) float64 { return v }
+
+// FBM2 returns fractal Brownian motion of the 2D noise function f, such as
+// Noise.Perlin2, in the range of f.
+func (fr Fractal) FBM2(f func(x, y float64) float64, x, y float64) float64 {
+	return fr.sum(func(freq float64) float64 { return f(x*freq, y*freq) }, identity)
+}
+
+// FBM3 returns fractal Brownian motion of the 3D noise function f.
+func (fr Fractal) FBM3(f func(x, y, z float64) float64, x, y, z float64) float64 {
+	return fr.sum(func(freq float64) float64 { return f(x*freq, y*freq, z*freq) }, identity)
+}
+
+// FBM4 returns fractal Brownian motion of the 4D noise function f.
+func (fr Fractal) FBM4(f func(x, y, z, w float64) float64, x, y, z, w float64) float64 {
+	return fr.sum(func(freq float64) float64 { return f(x*freq, y*freq, z*freq, w*freq) }, identity)
+}
+
+// Turbulence2 sums the absolute values of the octaves of the 2D noise
+// function f, which creases the pattern where f crosses zero. For noise in
+// [-1, 1] the result is in [0, 1].
+func (fr Fractal) Turbulence2(f func(x, y float64) float64, x, y float64) float64 {
+	return fr.sum(func(freq float64) float64 { return f(x*freq, y*freq) }, math.Abs)
+}
+
+// Turbulence3 sums the absolute values of the octaves of the 3D noise function f.
+func (fr Fractal) Turbulence3(f func(x, y, z float64) float64, x, y, z float64) float64 {
+	return fr.sum(func(freq float64) float64 { return f(x*freq, y*freq, z*freq) }, math.Abs)
+}
+
+// Turbulence4 sums the absolute values of the octaves of the 4D noise function f.
+func (fr Fractal) Turbulence4(f func(x, y, z, w float64) float64, x, y, z, w float64) float64 {
+	return fr.sum(func(freq float64) float64 { return f(x*freq, y*freq, z*freq, w*freq) }, math.Abs)
+}
@@ -0,0 +1,109 @@
+// Package noise generates seedable procedural noise: Perlin, simplex, value
+// and Worley (cellular) noise in two to four dimensions, fractal sums of
+// octaves, and textures such as clouds, marble and wood built from them.
+//
+// A third or fourth coordinate is commonly used as time, so that 2D or 3D
+// patterns evolve smoothly. Texture generators fill a quickcg image; pass the
+// screen buffer to draw on screen:
+//
+//	noise.Clouds(screen.BufferImage(), noise.New(42), 64, nil)
+//	screen.DrawBuffer()
+package noise
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Noise is a noise generator. Generators with the same seed produce the same
+// values, and different seeds give unrelated patterns.
+type Noise struct {
+	seed int64
+	perm [512]int // a permutation of 0-255, repeated to avoid wrapping indices
+}
+
+// New creates a noise generator from seed.
+func New(seed int64) *Noise {
+	n := &Noise{seed: seed}
+	p := rand.New(rand.NewSource(seed)).Perm(256)
+	for i := range n.perm {
+		n.perm[i] = p[i&255]
+	}
+	return n
+}
+
+// Seed returns the seed the generator was created with.
+func (n *Noise) Seed() int64 {
+	return n.seed
+}
+
+// fade is Perlin's quintic smoothstep 6t⁵ - 15t⁴ + 10t³, whose first and
+// second derivatives vanish at 0 and 1 so that lattice cells join invisibly.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// cell splits a coordinate into its lattice cell, wrapped to 0-255, and the
+// position inside it.
+func cell(x float64) (int, float64) {
+	f := math.Floor(x)
+	return int(f) & 255, x - f
+}
+
+// Value2 returns 2D value noise in [-1, 1]: random values at the integer
+// lattice points, smoothly interpolated. It is cheaper than Perlin noise but
+// shows more of the lattice.
+func (n *Noise) Value2(x, y float64) float64 {
+	xi, xf := cell(x)
+	yi, yf := cell(y)
+	p := &n.perm
+	v := func(dx, dy int) float64 {
+		return float64(p[p[xi+dx]+yi+dy])/127.5 - 1
+	}
+
+	u, w := fade(xf), fade(yf)
+	return lerp(lerp(v(0, 0), v(1, 0), u), lerp(v(0, 1), v(1, 1), u), w)
+}
+
+// Value3 returns 3D value noise in [-1, 1].
+func (n *Noise) Value3(x, y, z float64) float64 {
+	xi, xf := cell(x)
+	yi, yf := cell(y)
+	zi, zf := cell(z)
+	p := &n.perm
+	v := func(dx, dy, dz int) float64 {
+		return float64(p[p[p[xi+dx]+yi+dy]+zi+dz])/127.5 - 1
+	}
+
+	u, w, s := fade(xf), fade(yf), fade(zf)
+	return lerp(
+		lerp(lerp(v(0, 0, 0), v(1, 0, 0), u), lerp(v(0, 1, 0), v(1, 1, 0), u), w),
+		lerp(lerp(v(0, 0, 1), v(1, 0, 1), u), lerp(v(0, 1, 1), v(1, 1, 1), u), w),
+		s)
+}
+
+// Value4 returns 4D value noise in [-1, 1].
+func (n *Noise) Value4(x, y, z, w float64) float64 {
+	xi, xf := cell(x)
+	yi, yf := cell(y)
+	zi, zf := cell(z)
+	wi, wf := cell(w)
+	p := &n.perm
+	u, t, s := fade(xf), fade(yf), fade(zf)
+
+	// Blend two 3D slices whose lattice values also depend on the w cell.
+	slice := func(dw int) float64 {
+		v := func(dx, dy, dz int) float64 {
+			return float64(p[p[p[p[xi+dx]+yi+dy]+zi+dz]+wi+dw])/127.5 - 1
+		}
+		return lerp(
+			lerp(lerp(v(0, 0, 0), v(1, 0, 0), u), lerp(v(0, 1, 0), v(1, 1, 0), u), t),
+			lerp(lerp(v(0, 0, 1), v(1, 0, 1), u), lerp(v(0, 1, 1), v(1, 1, 1), u), t),
+			s)
+	}
+	return lerp(slice(0), slice(1), fade(wf))
+}
@@ -0,0 +1,140 @@
+package noise
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+)
+
+// noiseFunc evaluates one noise function of n at a point of up to four coordinates.
+type noiseFunc struct {
+	name string
+	f    func(n *Noise, p [4]float64) float64
+}
+
+var noiseFuncs = []noiseFunc{
+	{"Value2", func(n *Noise, p [4]float64) float64 { return n.Value2(p[0], p[1]) }},
+	{"Value3", func(n *Noise, p [4]float64) float64 { return n.Value3(p[0], p[1], p[2]) }},
+	{"Value4", func(n *Noise, p [4]float64) float64 { return n.Value4(p[0], p[1], p[2], p[3]) }},
+	{"Perlin2", func(n *Noise, p [4]float64) float64 { return n.Perlin2(p[0], p[1]) }},
+	{"Perlin3", func(n *Noise, p [4]float64) float64 { return n.Perlin3(p[0], p[1], p[2]) }},
+	{"Perlin4", func(n *Noise, p [4]float64) float64 { return n.Perlin4(p[0], p[1], p[2], p[3]) }},
+	{"Simplex2", func(n *Noise, p [4]float64) float64 { return n.Simplex2(p[0], p[1]) }},
+	{"Simplex3", func(n *Noise, p [4]float64) float64 { return n.Simplex3(p[0], p[1], p[2]) }},
+	{"Simplex4", func(n *Noise, p [4]float64) float64 { return n.Simplex4(p[0], p[1], p[2], p[3]) }},
+	{"Worley2", func(n *Noise, p [4]float64) float64 { return n.Worley2(p[0], p[1]) }},
+	{"Worley3", func(n *Noise, p [4]float64) float64 { return n.Worley3(p[0], p[1], p[2]) }},
+	{"Worley4", func(n *Noise, p [4]float64) float64 { return n.Worley4(p[0], p[1], p[2], p[3]) }},
+}
+
+// samplePoints returns reproducible points spread over several hundred
+// lattice cells, negative coordinates included.
+func samplePoints(count int) [][4]float64 {
+	rng := rand.New(rand.NewSource(7))
+	points := make([][4]float64, count)
+	for i := range points {
+		for k := range 4 {
+			points[i][k] = rng.Float64()*600 - 300
+		}
+	}
+	return points
+}
+
+func TestSeed(t *testing.T) {
+	points := samplePoints(200)
+	for _, nf := range noiseFuncs {
+		a, b, other := New(42), New(42), New(43)
+		differ := 0
+		for _, p := range points {
+			va := nf.f(a, p)
+			if vb := nf.f(b, p); va != vb {
+				t.Fatalf("%s: same seed gives %v and %v at %v", nf.name, va, vb, p)
+			}
+			if nf.f(other, p) != va {
+				differ++
+			}
+		}
+		if differ < len(points)*9/10 {
+			t.Errorf("%s: another seed changes only %d of %d values", nf.name, differ, len(points))
+		}
+	}
+	if s := New(-5).Seed(); s != -5 {
+		t.Errorf("Seed() = %d, want -5", s)
+	}
+}
+
+func TestRange(t *testing.T) {
+	points := samplePoints(20000)
+	n := New(1)
+	for _, nf := range noiseFuncs {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, p := range points {
+			v := nf.f(n, p)
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+
+		switch nf.name[:len(nf.name)-1] {
+		case "Value":
+			if lo < -1 || hi > 1 {
+				t.Errorf("%s: range [%v, %v] exceeds [-1, 1]", nf.name, lo, hi)
+			}
+		case "Worley":
+			// Distances to the nearest feature point, rarely above 1.
+			if lo < 0 || hi > 1.5 {
+				t.Errorf("%s: range [%v, %v], want about [0, 1]", nf.name, lo, hi)
+			}
+			continue
+		default:
+			// Gradient noise covers about [-1, 1]; 4D Perlin noise slightly exceeds it.
+			if lo < -1.2 || hi > 1.2 {
+				t.Errorf("%s: range [%v, %v], want about [-1, 1]", nf.name, lo, hi)
+			}
+		}
+		if lo > -0.6 || hi < 0.6 {
+			t.Errorf("%s: range [%v, %v] does not cover about [-1, 1]", nf.name, lo, hi)
+		}
+	}
+}
+
+func TestPerlinLattice(t *testing.T) {
+	n := New(3)
+	for _, p := range samplePoints(200) {
+		for k := range p {
+			p[k] = math.Floor(p[k])
+		}
+		for _, nf := range noiseFuncs[3:6] {
+			if v := nf.f(n, p); v != 0 {
+				t.Errorf("%s%v = %v, want 0 at lattice points", nf.name, p, v)
+			}
+		}
+	}
+}
+
+func TestFractal(t *testing.T) {
+	n := New(9)
+	for _, p := range samplePoints(2000) {
+		if v := DefaultFractal.Turbulence2(n.Perlin2, p[0], p[1]); v < 0 || v > 1 {
+			t.Fatalf("Turbulence2%v = %v, want [0, 1]", p[:2], v)
+		}
+		if v := DefaultFractal.FBM3(n.Simplex3, p[0], p[1], p[2]); math.Abs(v) > 1 {
+			t.Fatalf("FBM3%v = %v, want [-1, 1]", p[:3], v)
+		}
+	}
+}
+
+func TestTexturesNilColormap(t *testing.T) {
+	n := New(5)
+	var typedNil *quickcg.Gradient
+	for _, texture := range []func(*quickcg.Image, *Noise, float64, quickcg.Colormap){Clouds, Marble, Wood} {
+		a, b := quickcg.NewImage(16, 16), quickcg.NewImage(16, 16)
+		texture(a, n, 8, nil)
+		texture(b, n, 8, typedNil)
+		for i := range a.Pixels {
+			if a.Pixels[i] != b.Pixels[i] {
+				t.Fatalf("pixel %d differs between a nil colormap and a nil *Gradient", i)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package noise
+
+// The Perlin functions implement Ken Perlin's improved noise (2002): a
+// pseudo-random gradient at every integer lattice point, with the dot products
+// toward the sample point blended by the quintic fade curve. Results are
+// scaled to cover about [-1, 1] and are 0 at every lattice point.
+
+// grad2 holds the 2D gradient directions.
+var grad2 = [8][2]float64{
+	{1, 1}, {-1, 1}, {1, -1}, {-1, -1},
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+}
+
+// grad3 holds the 3D gradients, the midpoints of the edges of a cube.
+var grad3 = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+// grad4 holds the 4D gradients, the midpoints of the edges of a tesseract.
+var grad4 = [32][4]float64{
+	{0, 1, 1, 1}, {0, 1, 1, -1}, {0, 1, -1, 1}, {0, 1, -1, -1},
+	{0, -1, 1, 1}, {0, -1, 1, -1}, {0, -1, -1, 1}, {0, -1, -1, -1},
+	{1, 0, 1, 1}, {1, 0, 1, -1}, {1, 0, -1, 1}, {1, 0, -1, -1},
+	{-1, 0, 1, 1}, {-1, 0, 1, -1}, {-1, 0, -1, 1}, {-1, 0, -1, -1},
+	{1, 1, 0, 1}, {1, 1, 0, -1}, {1, -1, 0, 1}, {1, -1, 0, -1},
+	{-1, 1, 0, 1}, {-1, 1, 0, -1}, {-1, -1, 0, 1}, {-1, -1, 0, -1},
+	{1, 1, 1, 0}, {1, 1, -1, 0}, {1, -1, 1, 0}, {1, -1, -1, 0},
+	{-1, 1, 1, 0}, {-1, 1, -1, 0}, {-1, -1, 1, 0}, {-1, -1, -1, 0},
+}
+
+// Perlin2 returns 2D Perlin noise.
+func (n *Noise) Perlin2(x, y float64) float64 {
+	xi, xf := cell(x)
+	yi, yf := cell(y)
+	p := &n.perm
+	g := func(dx, dy int) float64 {
+		gr := grad2[p[p[xi+dx]+yi+dy]&7]
+		return gr[0]*(xf-float64(dx)) + gr[1]*(yf-float64(dy))
+	}
+
+	u, v := fade(xf), fade(yf)
+	return lerp(lerp(g(0, 0), g(1, 0), u), lerp(g(0, 1), g(1, 1), u), v)
+}
+
+// Perlin3 returns 3D Perlin noise.
+func (n *Noise) Perlin3(x, y, z float64) float64 {
+	xi, xf := cell(x)
+	yi, yf := cell(y)
+	zi, zf := cell(z)
+	p := &n.perm
+	g := func(dx, dy, dz int) float64 {
+		gr := grad3[p[p[p[xi+dx]+yi+dy]+zi+dz]%12]
+		return gr[0]*(xf-float64(dx)) + gr[1]*(yf-float64(dy)) + gr[2]*(zf-float64(dz))
+	}
+
+	u, v, w := fade(xf), fade(yf), fade(zf)
+	return lerp(
+		lerp(lerp(g(0, 0, 0), g(1, 0, 0), u), lerp(g(0, 1, 0), g(1, 1, 0), u), v),
+		lerp(lerp(g(0, 0, 1), g(1, 0, 1), u), lerp(g(0, 1, 1), g(1, 1, 1), u), v),
+		w)
+}
+
+// Perlin4 returns 4D Perlin noise.
+func (n *Noise) Perlin4(x, y, z, w float64) float64 {
+	xi, xf := cell(x)
+	yi, yf := cell(y)
+	zi, zf := cell(z)
+	wi, wf := cell(w)
+	p := &n.perm
+	g := func(dx, dy, dz, dw int) float64 {
+		gr := grad4[p[p[p[p[xi+dx]+yi+dy]+zi+dz]+wi+dw]&31]
+		return gr[0]*(xf-float64(dx)) + gr[1]*(yf-float64(dy)) +
+			gr[2]*(zf-float64(dz)) + gr[3]*(wf-float64(dw))
+	}
+
+	u, v, s, t := fade(xf), fade(yf), fade(zf), fade(wf)
+	slice := func(dw int) float64 {
+		return lerp(
+			lerp(lerp(g(0, 0, 0, dw), g(1, 0, 0, dw), u), lerp(g(0, 1, 0, dw), g(1, 1, 0, dw), u), v),
+			lerp(lerp(g(0, 0, 1, dw), g(1, 0, 1, dw), u), lerp(g(0, 1, 1, dw), g(1, 1, 1, dw), u), v),
+			s)
+	}
+	return lerp(slice(0), slice(1), t)
+}
@@ -0,0 +1,160 @@
+package noise
+
+import (
+	"math"
+)
+
+// The Simplex functions implement Ken Perlin's simplex noise following
+// Stefan Gustavson's reference implementation. Instead of a square lattice
+// they use triangles, tetrahedra and their 4D analogue, which needs fewer
+// gradients per sample than Perlin noise, scales better to four dimensions
+// and has no axis-aligned artifacts. Results cover about [-1, 1].
+
+var (
+	f2 = 0.5 * (math.Sqrt(3) - 1)
+	g2 = (3 - math.Sqrt(3)) / 6
+	f4 = (math.Sqrt(5) - 1) / 4
+	g4 = (5 - math.Sqrt(5)) / 20
+)
+
+const (
+	f3 = 1.0 / 3
+	g3 = 1.0 / 6
+)
+
+// Simplex2 returns 2D simplex noise.
+func (n *Noise) Simplex2(x, y float64) float64 {
+	// Skew the input space to find the simplex cell containing the point.
+	s := (x + y) * f2
+	i, j := math.Floor(x+s), math.Floor(y+s)
+	t := (i + j) * g2
+	x0, y0 := x-(i-t), y-(j-t)
+
+	// Pick the middle corner of the triangle.
+	var i1, j1 int
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+
+	ii, jj := int(i)&255, int(j)&255
+	p := &n.perm
+	corner := func(di, dj int, x, y float64) float64 {
+		t := 0.5 - x*x - y*y
+		if t < 0 {
+			return 0
+		}
+		g := grad3[p[ii+di+p[jj+dj]]%12]
+		t *= t
+		return t * t * (g[0]*x + g[1]*y)
+	}
+
+	return 70 * (corner(0, 0, x0, y0) +
+		corner(i1, j1, x0-float64(i1)+g2, y0-float64(j1)+g2) +
+		corner(1, 1, x0-1+2*g2, y0-1+2*g2))
+}
+
+// Simplex3 returns 3D simplex noise.
+func (n *Noise) Simplex3(x, y, z float64) float64 {
+	s := (x + y + z) * f3
+	i, j, k := math.Floor(x+s), math.Floor(y+s), math.Floor(z+s)
+	t := (i + j + k) * g3
+	x0, y0, z0 := x-(i-t), y-(j-t), z-(k-t)
+
+	// Order the coordinates to find the second and third corners of the
+	// tetrahedron.
+	var i1, j1, k1, i2, j2, k2 int
+	if x0 >= y0 {
+		switch {
+		case y0 >= z0:
+			i1, i2, j2 = 1, 1, 1
+		case x0 >= z0:
+			i1, i2, k2 = 1, 1, 1
+		default:
+			k1, i2, k2 = 1, 1, 1
+		}
+	} else {
+		switch {
+		case y0 < z0:
+			k1, j2, k2 = 1, 1, 1
+		case x0 < z0:
+			j1, j2, k2 = 1, 1, 1
+		default:
+			j1, i2, j2 = 1, 1, 1
+		}
+	}
+
+	ii, jj, kk := int(i)&255, int(j)&255, int(k)&255
+	p := &n.perm
+	corner := func(di, dj, dk int, off float64) float64 {
+		x := x0 - float64(di) + off
+		y := y0 - float64(dj) + off
+		z := z0 - float64(dk) + off
+		t := 0.6 - x*x - y*y - z*z
+		if t < 0 {
+			return 0
+		}
+		g := grad3[p[ii+di+p[jj+dj+p[kk+dk]]]%12]
+		t *= t
+		return t * t * (g[0]*x + g[1]*y + g[2]*z)
+	}
+
+	return 32 * (corner(0, 0, 0, 0) +
+		corner(i1, j1, k1, g3) +
+		corner(i2, j2, k2, 2*g3) +
+		corner(1, 1, 1, 3*g3))
+}
+
+// Simplex4 returns 4D simplex noise.
+func (n *Noise) Simplex4(x, y, z, w float64) float64 {
+	s := (x + y + z + w) * f4
+	i, j, k, l := math.Floor(x+s), math.Floor(y+s), math.Floor(z+s), math.Floor(w+s)
+	t := (i + j + k + l) * g4
+	x0, y0, z0, w0 := x-(i-t), y-(j-t), z-(k-t), w-(l-t)
+
+	// Rank the coordinates by size; the simplex corners step along the
+	// axes from the largest coordinate to the smallest.
+	var rank [4]int
+	c := [4]float64{x0, y0, z0, w0}
+	for a := range 4 {
+		for b := a + 1; b < 4; b++ {
+			if c[a] > c[b] {
+				rank[a]++
+			} else {
+				rank[b]++
+			}
+		}
+	}
+	step := func(threshold int) [4]int {
+		var d [4]int
+		for a, r := range rank {
+			if r >= threshold {
+				d[a] = 1
+			}
+		}
+		return d
+	}
+
+	ii, jj, kk, ll := int(i)&255, int(j)&255, int(k)&255, int(l)&255
+	p := &n.perm
+	corner := func(d [4]int, off float64) float64 {
+		x := x0 - float64(d[0]) + off
+		y := y0 - float64(d[1]) + off
+		z := z0 - float64(d[2]) + off
+		w := w0 - float64(d[3]) + off
+		t := 0.6 - x*x - y*y - z*z - w*w
+		if t < 0 {
+			return 0
+		}
+		g := grad4[p[ii+d[0]+p[jj+d[1]+p[kk+d[2]+p[ll+d[3]]]]]&31]
+		t *= t
+		return t * t * (g[0]*x + g[1]*y + g[2]*z + g[3]*w)
+	}
+
+	return 27 * (corner([4]int{}, 0) +
+		corner(step(3), g4) +
+		corner(step(2), 2*g4) +
+		corner(step(1), 3*g4) +
+		corner([4]int{1, 1, 1, 1}, 4*g4))
+}
@@ -0,0 +1,74 @@
+package noise
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+)
+
+// CloudColors returns the default colormap of Clouds, from sky blue to white.
+func CloudColors() *quickcg.Gradient {
+	return quickcg.NewGradient(quickcg.InterpolateOKLab,
+		quickcg.ColorRGB{R: 40, G: 90, B: 190}, quickcg.ColorRGB{R: 255, G: 255, B: 255})
+}
+
+// MarbleColors returns the default colormap of Marble, from dark gray veins
+// to off-white stone.
+func MarbleColors() *quickcg.Gradient {
+	return quickcg.NewGradient(quickcg.InterpolateRGB,
+		quickcg.ColorRGB{R: 70, G: 70, B: 80}, quickcg.ColorRGB{R: 235, G: 235, B: 230})
+}
+
+// WoodColors returns the default colormap of Wood, from dark to light brown.
+func WoodColors() *quickcg.Gradient {
+	return quickcg.NewGradient(quickcg.InterpolateRGB,
+		quickcg.ColorRGB{R: 110, G: 60, B: 25}, quickcg.ColorRGB{R: 200, G: 140, B: 80})
+}
+
+// colormapOr returns cmap, or def if cmap is nil. A nil *quickcg.Gradient
+// stored in cmap counts as nil too, since the interface holding it is not.
+func colormapOr(cmap quickcg.Colormap, def func() *quickcg.Gradient) quickcg.Colormap {
+	if g, ok := cmap.(*quickcg.Gradient); cmap == nil || ok && g == nil {
+		return def()
+	}
+	return cmap
+}
+
+// fillTexture sets every pixel of img to cmap at t(x, y).
+func fillTexture(img *quickcg.Image, cmap quickcg.Colormap, t func(x, y float64) float64) {
+	for y := range img.H {
+		for x := range img.W {
+			img.Pixels[y*img.W+x] = cmap.At(t(float64(x), float64(y)))
+		}
+	}
+}
+
+// Clouds fills img with fractal Perlin noise, where scale is the size in
+// pixels of the largest puffs. A nil cmap uses CloudColors.
+func Clouds(img *quickcg.Image, n *Noise, scale float64, cmap quickcg.Colormap) {
+	fillTexture(img, colormapOr(cmap, CloudColors), func(x, y float64) float64 {
+		return 0.5 + 0.5*DefaultFractal.FBM2(n.Perlin2, x/scale, y/scale)
+	})
+}
+
+// Marble fills img with diagonal veins, a sine pattern distorted by
+// turbulence, where scale is the distance in pixels between veins.
+// A nil cmap uses MarbleColors.
+func Marble(img *quickcg.Image, n *Noise, scale float64, cmap quickcg.Colormap) {
+	fillTexture(img, colormapOr(cmap, MarbleColors), func(x, y float64) float64 {
+		turb := DefaultFractal.Turbulence2(n.Perlin2, x/(4*scale), y/(4*scale))
+		return math.Abs(math.Sin((x+y)/scale*math.Pi/2 + 5*turb))
+	})
+}
+
+// Wood fills img with growth rings around the center of the image, made
+// irregular by turbulence, where scale is the distance in pixels between
+// rings. A nil cmap uses WoodColors.
+func Wood(img *quickcg.Image, n *Noise, scale float64, cmap quickcg.Colormap) {
+	cx, cy := float64(img.W)/2, float64(img.H)/2
+	fillTexture(img, colormapOr(cmap, WoodColors), func(x, y float64) float64 {
+		turb := DefaultFractal.Turbulence2(n.Perlin2, x/(4*scale), y/(4*scale))
+		d := math.Hypot(x-cx, y-cy)/scale + 0.8*turb
+		return math.Abs(math.Sin(d * math.Pi))
+	})
+}
@@ -0,0 +1,84 @@
+package noise
+
+import (
+	"math"
+)
+
+// The Worley functions implement Steven Worley's cellular noise: space is
+// scattered with one random feature point per unit cell, and the result is
+// the distance from the sample point to the nearest one. It is 0 on the
+// feature points and rarely exceeds 1, and looks like cells, stones or
+// scales. Use 1 - Worley for bright cells on dark borders.
+
+// hash mixes the seed and up to four lattice coordinates into 64 random bits
+// with the splitmix64 finalizer.
+func (n *Noise) hash(x, y, z, w int) uint64 {
+	h := uint64(n.seed)
+	for _, c := range [4]int{x, y, z, w} {
+		h ^= uint64(c) * 0x9e3779b97f4a7c15
+		h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+		h = (h ^ h>>27) * 0x94d049bb133111eb
+		h ^= h >> 31
+	}
+	return h
+}
+
+// feature returns the offset of the feature point of a cell, with each
+// coordinate in [0, 1) taken from a different 16 bits of the cell hash.
+func feature(h uint64) [4]float64 {
+	return [4]float64{
+		float64(h&0xffff) / 65536,
+		float64(h>>16&0xffff) / 65536,
+		float64(h>>32&0xffff) / 65536,
+		float64(h>>48) / 65536,
+	}
+}
+
+// Worley2 returns 2D cellular noise.
+func (n *Noise) Worley2(x, y float64) float64 {
+	cx, cy := math.Floor(x), math.Floor(y)
+	best := math.Inf(1)
+	for dy := -1.0; dy <= 1; dy++ {
+		for dx := -1.0; dx <= 1; dx++ {
+			f := feature(n.hash(int(cx+dx), int(cy+dy), 0, 0))
+			px, py := cx+dx+f[0]-x, cy+dy+f[1]-y
+			best = math.Min(best, px*px+py*py)
+		}
+	}
+	return math.Sqrt(best)
+}
+
+// Worley3 returns 3D cellular noise.
+func (n *Noise) Worley3(x, y, z float64) float64 {
+	cx, cy, cz := math.Floor(x), math.Floor(y), math.Floor(z)
+	best := math.Inf(1)
+	for dz := -1.0; dz <= 1; dz++ {
+		for dy := -1.0; dy <= 1; dy++ {
+			for dx := -1.0; dx <= 1; dx++ {
+				f := feature(n.hash(int(cx+dx), int(cy+dy), int(cz+dz), 0))
+				px, py, pz := cx+dx+f[0]-x, cy+dy+f[1]-y, cz+dz+f[2]-z
+				best = math.Min(best, px*px+py*py+pz*pz)
+			}
+		}
+	}
+	return math.Sqrt(best)
+}
+
+// Worley4 returns 4D cellular noise.
+func (n *Noise) Worley4(x, y, z, w float64) float64 {
+	cx, cy, cz, cw := math.Floor(x), math.Floor(y), math.Floor(z), math.Floor(w)
+	best := math.Inf(1)
+	for dw := -1.0; dw <= 1; dw++ {
+		for dz := -1.0; dz <= 1; dz++ {
+			for dy := -1.0; dy <= 1; dy++ {
+				for dx := -1.0; dx <= 1; dx++ {
+					f := feature(n.hash(int(cx+dx), int(cy+dy), int(cz+dz), int(cw+dw)))
+					px, py := cx+dx+f[0]-x, cy+dy+f[1]-y
+					pz, pw := cz+dz+f[2]-z, cw+dw+f[3]-w
+					best = math.Min(best, px*px+py*py+pz*pz+pw*pw)
+				}
+			}
+		}
+	}
+	return math.Sqrt(best)
+}
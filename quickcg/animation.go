@@ -0,0 +1,258 @@
+package quickcg
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"os"
+)
+
+// AnimationMode controls what an Animation does after its last frame.
+type AnimationMode int
+
+const (
+	// AnimationLoop restarts from the first frame.
+	AnimationLoop AnimationMode = iota
+	// AnimationPingPong plays the frames backwards, then forwards again.
+	AnimationPingPong
+	// AnimationOnce stops on the last frame.
+	AnimationOnce
+)
+
+// defaultFrameDelay is used for GIF frames without a delay, as browsers do.
+const defaultFrameDelay = 0.1
+
+// AnimationFrame is a single frame of an Animation.
+type AnimationFrame struct {
+	Image *Image
+	Mask  []bool  // opaque pixels of Image; nil means fully opaque
+	Delay float64 // display time in seconds
+}
+
+// Animation plays a sequence of frames with individual delays.
+type Animation struct {
+	Frames []AnimationFrame
+	Mode   AnimationMode
+
+	current  int
+	elapsed  float64
+	reverse  bool // ping-pong is playing backwards
+	finished bool
+}
+
+// NewAnimation creates an animation from already prepared frames.
+func NewAnimation(frames []AnimationFrame, mode AnimationMode) *Animation {
+	return &Animation{Frames: frames, Mode: mode}
+}
+
+// LoadGIFAnimation loads all frames of an animated GIF. Frames are composited
+// according to their disposal methods, so every AnimationFrame holds the
+// complete picture; transparent pixels are left out of the frame mask.
+func LoadGIFAnimation(path string) (*Animation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading file: %s", err)
+	}
+	defer file.Close()
+
+	decoded, err := gif.DecodeAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("Error decoding file: %s", err)
+	}
+
+	w, h := decoded.Config.Width, decoded.Config.Height
+	canvas := NewImage(w, h)
+	mask := make([]bool, w*h)
+
+	var savedCanvas []ColorRGB
+	var savedMask []bool
+
+	frames := make([]AnimationFrame, 0, len(decoded.Image))
+	for i, src := range decoded.Image {
+		disposal := byte(gif.DisposalNone)
+		if i < len(decoded.Disposal) {
+			disposal = decoded.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			savedCanvas = append(savedCanvas[:0], canvas.Pixels...)
+			savedMask = append(savedMask[:0], mask...)
+		}
+
+		bounds := src.Bounds().Intersect(image.Rect(0, 0, w, h))
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, a := src.Palette[src.ColorIndexAt(x, y)].RGBA()
+				if a == 0 {
+					continue
+				}
+				canvas.Pixels[y*w+x] = ColorRGB{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}
+				mask[y*w+x] = true
+			}
+		}
+
+		delay := defaultFrameDelay
+		if i < len(decoded.Delay) && decoded.Delay[i] > 0 {
+			delay = float64(decoded.Delay[i]) / 100
+		}
+		frames = append(frames, AnimationFrame{
+			Image: canvas.Clone(),
+			Mask:  append([]bool(nil), mask...),
+			Delay: delay,
+		})
+
+		switch disposal {
+		case gif.DisposalBackground:
+			for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+				for x := bounds.Min.X; x < bounds.Max.X; x++ {
+					canvas.Pixels[y*w+x] = ColorRGB{}
+					mask[y*w+x] = false
+				}
+			}
+		case gif.DisposalPrevious:
+			copy(canvas.Pixels, savedCanvas)
+			copy(mask, savedMask)
+		}
+	}
+
+	return NewAnimation(frames, AnimationLoop), nil
+}
+
+// NewSpriteSheetAnimation cuts a sprite sheet into frames of frameW x frameH
+// pixels, read left to right and top to bottom. One frame is created for
+// each entry of delays, which holds the display time of that frame in seconds.
+func NewSpriteSheetAnimation(sheet *Image, frameW, frameH int, delays []float64, mode AnimationMode) (*Animation, error) {
+	if frameW <= 0 || frameH <= 0 {
+		return nil, fmt.Errorf("invalid frame size %dx%d", frameW, frameH)
+	}
+
+	cols := sheet.W / frameW
+	rows := sheet.H / frameH
+	if len(delays) > cols*rows {
+		return nil, fmt.Errorf("sprite sheet holds %d frames, %d requested", cols*rows, len(delays))
+	}
+
+	frames := make([]AnimationFrame, len(delays))
+	for i, delay := range delays {
+		frame := NewImage(frameW, frameH)
+		ox := (i % cols) * frameW
+		oy := (i / cols) * frameH
+		for y := range frameH {
+			copy(frame.Pixels[y*frameW:(y+1)*frameW], sheet.Pixels[(oy+y)*sheet.W+ox:])
+		}
+		if delay <= 0 {
+			delay = defaultFrameDelay
+		}
+		frames[i] = AnimationFrame{Image: frame, Delay: delay}
+	}
+
+	return NewAnimation(frames, mode), nil
+}
+
+// SetTransparentColor makes all pixels of the given color transparent in every frame.
+// Use it for sprite sheets, which lose their alpha channel when loaded with LoadPNG.
+func (anim *Animation) SetTransparentColor(key ColorRGB) {
+	for i := range anim.Frames {
+		frame := &anim.Frames[i]
+		if frame.Mask == nil {
+			frame.Mask = make([]bool, len(frame.Image.Pixels))
+			for j := range frame.Mask {
+				frame.Mask[j] = true
+			}
+		}
+		for j, c := range frame.Image.Pixels {
+			if c == key {
+				frame.Mask[j] = false
+			}
+		}
+	}
+}
+
+// Update advances the animation by dt seconds.
+func (anim *Animation) Update(dt float64) {
+	if anim.finished || len(anim.Frames) == 0 {
+		return
+	}
+
+	anim.elapsed += dt
+	for !anim.finished && anim.elapsed >= anim.delay() {
+		anim.elapsed -= anim.delay()
+		anim.advance()
+	}
+}
+
+// delay returns the display time of the current frame,
+// falling back to defaultFrameDelay for frames without one.
+func (anim *Animation) delay() float64 {
+	if d := anim.Frames[anim.current].Delay; d > 0 {
+		return d
+	}
+	return defaultFrameDelay
+}
+
+func (anim *Animation) advance() {
+	n := len(anim.Frames)
+
+	switch anim.Mode {
+	case AnimationOnce:
+		if anim.current == n-1 {
+			anim.finished = true
+			anim.elapsed = 0
+			return
+		}
+		anim.current++
+	case AnimationPingPong:
+		if n == 1 {
+			return
+		}
+		if anim.current == 0 {
+			anim.reverse = false
+		} else if anim.current == n-1 {
+			anim.reverse = true
+		}
+		if anim.reverse {
+			anim.current--
+		} else {
+			anim.current++
+		}
+	default:
+		anim.current = (anim.current + 1) % n
+	}
+}
+
+// Reset rewinds the animation to its first frame.
+func (anim *Animation) Reset() {
+	anim.current = 0
+	anim.elapsed = 0
+	anim.reverse = false
+	anim.finished = false
+}
+
+// Frame returns the index of the frame currently shown.
+func (anim *Animation) Frame() int {
+	return anim.current
+}
+
+// Finished reports whether an AnimationOnce animation has reached its end.
+func (anim *Animation) Finished() bool {
+	return anim.finished
+}
+
+// Draw writes the current frame into the screen buffer with its top-left
+// corner at (x, y), skipping transparent pixels. Call DrawBuffer to show it.
+func (anim *Animation) Draw(screen *Screen, x, y int) {
+	if len(anim.Frames) == 0 {
+		return
+	}
+
+	frame := anim.Frames[anim.current]
+	img := frame.Image
+	for py := range img.H {
+		for px := range img.W {
+			i := py*img.W + px
+			if frame.Mask != nil && !frame.Mask[i] {
+				continue
+			}
+			screen.WritePixel(x+px, y+py, img.Pixels[i])
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package quickcg
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+)
+
+// Atlas is a single image holding many smaller images in named regions.
+type Atlas struct {
+	Image   *Image
+	Regions map[string]Rect
+}
+
+// atlasLayout is the JSON representation of an atlas layout.
+type atlasLayout struct {
+	Width   int             `json:"width"`
+	Height  int             `json:"height"`
+	Regions map[string]Rect `json:"regions"`
+}
+
+// skylineSegment is a horizontal piece of the skyline used by PackAtlas.
+type skylineSegment struct {
+	x, y, w int
+}
+
+// PackAtlas combines the named images into one atlas no wider than maxWidth,
+// leaving padding pixels between neighbouring images. It uses a bottom-left
+// skyline packer, placing the tallest images first.
+func PackAtlas(images map[string]*Image, maxWidth, padding int) (*Atlas, error) {
+	names := make([]string, 0, len(images))
+	for name, img := range images {
+		if img.W+padding > maxWidth {
+			return nil, fmt.Errorf("image %q is %d pixels wide, atlas allows %d", name, img.W, maxWidth-padding)
+		}
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if d := images[b].H - images[a].H; d != 0 {
+			return d
+		}
+		if a < b {
+			return -1
+		}
+		return 1
+	})
+
+	skyline := []skylineSegment{{x: 0, y: 0, w: maxWidth}}
+	regions := make(map[string]Rect, len(images))
+	width, height := 0, 0
+
+	for _, name := range names {
+		img := images[name]
+		w, h := img.W+padding, img.H+padding
+
+		bestX, bestY, bestIdx := 0, -1, -1
+		for i, seg := range skyline {
+			if seg.x+w > maxWidth {
+				break
+			}
+			y := skylineHeight(skyline, i, w)
+			if bestIdx < 0 || y+h < bestY+h {
+				bestX, bestY, bestIdx = seg.x, y, i
+			}
+		}
+
+		skyline = skylineInsert(skyline, skylineSegment{x: bestX, y: bestY + h, w: w})
+		regions[name] = Rect{X: bestX, Y: bestY, W: img.W, H: img.H}
+		width = max(width, bestX+img.W)
+		height = max(height, bestY+img.H)
+	}
+
+	atlas := &Atlas{Image: NewImage(width, height), Regions: regions}
+	for name, r := range regions {
+		src := images[name]
+		for y := range r.H {
+			copy(atlas.Image.Pixels[(r.Y+y)*width+r.X:], src.Pixels[y*src.W:(y+1)*src.W])
+		}
+	}
+
+	return atlas, nil
+}
+
+// skylineHeight returns the lowest y at which a rectangle of width w fits
+// when its left edge starts at segment i.
+func skylineHeight(skyline []skylineSegment, i, w int) int {
+	y := 0
+	end := skyline[i].x + w
+	for j := i; j < len(skyline) && skyline[j].x < end; j++ {
+		y = max(y, skyline[j].y)
+	}
+	return y
+}
+
+// skylineInsert raises the skyline under the new segment and merges
+// neighbouring segments of equal height.
+func skylineInsert(skyline []skylineSegment, seg skylineSegment) []skylineSegment {
+	end := seg.x + seg.w
+	out := make([]skylineSegment, 0, len(skyline)+2)
+
+	for _, s := range skyline {
+		sEnd := s.x + s.w
+		if sEnd <= seg.x || s.x >= end {
+			out = append(out, s)
+			continue
+		}
+		if s.x < seg.x {
+			out = append(out, skylineSegment{x: s.x, y: s.y, w: seg.x - s.x})
+		}
+		if sEnd > end {
+			out = append(out, skylineSegment{x: end, y: s.y, w: sEnd - end})
+		}
+	}
+
+	out = append(out, seg)
+	slices.SortFunc(out, func(a, b skylineSegment) int { return a.x - b.x })
+
+	merged := out[:1]
+	for _, s := range out[1:] {
+		last := &merged[len(merged)-1]
+		if last.y == s.y {
+			last.w += s.w
+			continue
+		}
+		merged = append(merged, s)
+	}
+
+	return merged
+}
+
+// SliceSheet cuts a sprite sheet into a grid of frameW x frameH cells and
+// returns it as an atlas. Cells are named left to right and top to bottom
+// with the entries of names; with nil names they are numbered from "0".
+func SliceSheet(sheet *Image, frameW, frameH int, names []string) (*Atlas, error) {
+	if frameW <= 0 || frameH <= 0 {
+		return nil, fmt.Errorf("invalid frame size %dx%d", frameW, frameH)
+	}
+
+	cols := sheet.W / frameW
+	rows := sheet.H / frameH
+	if names == nil {
+		names = make([]string, cols*rows)
+		for i := range names {
+			names[i] = strconv.Itoa(i)
+		}
+	}
+	if len(names) > cols*rows {
+		return nil, fmt.Errorf("sprite sheet holds %d frames, %d names given", cols*rows, len(names))
+	}
+
+	regions := make(map[string]Rect, len(names))
+	for i, name := range names {
+		regions[name] = Rect{X: (i % cols) * frameW, Y: (i / cols) * frameH, W: frameW, H: frameH}
+	}
+
+	return &Atlas{Image: sheet, Regions: regions}, nil
+}
+
+// Sub returns a copy of the named region.
+func (atlas *Atlas) Sub(name string) (*Image, error) {
+	r, ok := atlas.Regions[name]
+	if !ok {
+		return nil, fmt.Errorf("atlas has no region %q", name)
+	}
+	return atlas.Image.Crop(r), nil
+}
+
+// Draw writes the named region into the screen buffer with its top-left
+// corner at (x, y). Call DrawBuffer to show it.
+func (atlas *Atlas) Draw(screen *Screen, name string, x, y int) error {
+	r, ok := atlas.Regions[name]
+	if !ok {
+		return fmt.Errorf("atlas has no region %q", name)
+	}
+
+	for py := range r.H {
+		for px := range r.W {
+			screen.WritePixel(x+px, y+py, atlas.Image.At(r.X+px, r.Y+py))
+		}
+	}
+	return nil
+}
+
+// SaveLayout writes the atlas size and regions to a JSON file.
+// Save the atlas image separately with Image.SavePNG.
+func (atlas *Atlas) SaveLayout(path string) error {
+	data, err := json.MarshalIndent(atlasLayout{
+		Width:   atlas.Image.W,
+		Height:  atlas.Image.H,
+		Regions: atlas.Regions,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode layout: %w", err)
+	}
+
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write layout: %w", err)
+	}
+
+	return nil
+}
+
+// LoadAtlas loads an atlas image from a PNG file and its layout from a JSON
+// file written by SaveLayout.
+func LoadAtlas(imagePath, layoutPath string) (*Atlas, error) {
+	img, err := LoadImage(imagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(layoutPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout: %w", err)
+	}
+
+	var layout atlasLayout
+	err = json.Unmarshal(data, &layout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode layout: %w", err)
+	}
+
+	if layout.Width != img.W || layout.Height != img.H {
+		return nil, fmt.Errorf("layout is for a %dx%d atlas, image is %dx%d", layout.Width, layout.Height, img.W, img.H)
+	}
+	for name, r := range layout.Regions {
+		if r.X < 0 || r.Y < 0 || r.W < 0 || r.H < 0 || r.X+r.W > img.W || r.Y+r.H > img.H {
+			return nil, fmt.Errorf("region %q lies outside the atlas", name)
+		}
+	}
+
+	return &Atlas{Image: img, Regions: layout.Regions}, nil
+}
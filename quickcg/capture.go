@@ -0,0 +1,142 @@
+package quickcg
+
+// CaptureSource selects which pixels Capture reads.
+type CaptureSource int
+
+const (
+	// CaptureBuffer reads the logical pixel buffer written by WritePixel.
+	CaptureBuffer CaptureSource = iota
+	// CaptureFrame reads the frame shown by the last Redraw.
+	CaptureFrame
+)
+
+// CaptureOptions configures Capture and SaveCapture.
+type CaptureOptions struct {
+	Source CaptureSource
+	// IncludeOverlays composites pixels drawn in immediate mode
+	// (PSet, DrawLine, DrawRect, DrawText, ...) over the captured pixels.
+	IncludeOverlays bool
+}
+
+// Capture returns a copy of the screen contents selected by opts.
+//
+// Unlike SaveScreenAsPNG, Capture never reads back from the GPU. The screen
+// keeps a CPU copy of everything it sends to the renderer, so the result is
+// the same for accelerated and software renderers and does not depend on
+// what the backend keeps in its back buffer.
+func (screen *Screen) Capture(opts CaptureOptions) *Image {
+	img := NewImage(screen.w, screen.h)
+
+	switch opts.Source {
+	case CaptureFrame:
+		if opts.IncludeOverlays {
+			copy(img.Pixels, screen.presented)
+		} else {
+			copy(img.Pixels, screen.presentedBase)
+		}
+	default:
+		copy(img.Pixels, screen.buffer)
+		if opts.IncludeOverlays {
+			for i, drawn := range screen.overlay {
+				if drawn {
+					img.Pixels[i] = screen.frame[i]
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// SaveCapture writes the screen contents selected by opts to a PNG file at the given path.
+func (screen *Screen) SaveCapture(path string, opts CaptureOptions) error {
+	return screen.Capture(opts).SavePNG(path)
+}
+
+// allocFrames allocates the CPU copies of the render target.
+func (screen *Screen) allocFrames() {
+	n := screen.w * screen.h
+	screen.frame = make([]ColorRGB, n)
+	screen.base = make([]ColorRGB, n)
+	screen.overlay = make([]bool, n)
+	screen.presented = make([]ColorRGB, n)
+	screen.presentedBase = make([]ColorRGB, n)
+}
+
+// markPixel records an immediate-mode pixel in the CPU copy of the render target.
+func (screen *Screen) markPixel(x, y int, color ColorRGB) {
+	if x < 0 || y < 0 || x >= screen.w || y >= screen.h {
+		return
+	}
+	i := y*screen.w + x
+	screen.frame[i] = color
+	screen.overlay[i] = true
+}
+
+// markLine records a line drawn by the renderer, endpoints included.
+func (screen *Screen) markLine(x1, y1, x2, y2 int, color ColorRGB) {
+	dx := abs(x2 - x1)
+	dy := -abs(y2 - y1)
+	sx, sy := 1, 1
+	if x1 > x2 {
+		sx = -1
+	}
+	if y1 > y2 {
+		sy = -1
+	}
+
+	e := dx + dy
+	for {
+		screen.markPixel(x1, y1, color)
+		if x1 == x2 && y1 == y2 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x1 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y1 += sy
+		}
+	}
+}
+
+// markRect records a filled rectangle covering [x1, x2) x [y1, y2).
+func (screen *Screen) markRect(x1, y1, x2, y2 int, color ColorRGB) {
+	for y := max(y1, 0); y < min(y2, screen.h); y++ {
+		for x := max(x1, 0); x < min(x2, screen.w); x++ {
+			screen.markPixel(x, y, color)
+		}
+	}
+}
+
+// resetFrame replaces the render target copy with src and drops all overlays.
+func (screen *Screen) resetFrame(src []ColorRGB) {
+	copy(screen.frame, src)
+	copy(screen.base, src)
+	clear(screen.overlay)
+}
+
+// clearFrame fills the render target copy with color and drops all overlays.
+func (screen *Screen) clearFrame(color ColorRGB) {
+	for i := range screen.frame {
+		screen.frame[i] = color
+		screen.base[i] = color
+	}
+	clear(screen.overlay)
+}
+
+// presentFrame remembers the render target copy as the presented frame.
+func (screen *Screen) presentFrame() {
+	copy(screen.presented, screen.frame)
+	copy(screen.presentedBase, screen.base)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
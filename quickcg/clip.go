@@ -0,0 +1,260 @@
+package quickcg
+
+import "github.com/RostislavArts/quickcgo/vecmath"
+
+// Empty reports whether the rectangle covers no pixels.
+func (r Rect) Empty() bool {
+	return r.W <= 0 || r.H <= 0
+}
+
+// Contains reports whether the pixel (x, y) lies inside the rectangle.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && y >= r.Y && x < r.X+r.W && y < r.Y+r.H
+}
+
+// Intersect returns the part of r that also lies in o.
+// The result is empty if the rectangles do not overlap.
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.W, o.X+o.W), min(r.Y+r.H, o.Y+o.H)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{}
+	}
+	return Rect{X: x0, Y: y0, W: x1 - x0, H: y1 - y0}
+}
+
+// RectFromCorners returns the rectangle covering [x1, x2) x [y1, y2),
+// accepting the corners in any order.
+func RectFromCorners(x1, y1, x2, y2 int) Rect {
+	return Rect{X: min(x1, x2), Y: min(y1, y2), W: abs(x2 - x1), H: abs(y2 - y1)}
+}
+
+// ClipRect clips r against clip. It returns false if nothing is left.
+func ClipRect(r, clip Rect) (Rect, bool) {
+	r = r.Intersect(clip)
+	return r, !r.Empty()
+}
+
+// Cohen–Sutherland outcodes.
+const (
+	outLeft = 1 << iota
+	outRight
+	outTop
+	outBottom
+)
+
+func outcode(x, y int, r Rect) int {
+	code := 0
+	if x < r.X {
+		code |= outLeft
+	} else if x > r.X+r.W-1 {
+		code |= outRight
+	}
+	if y < r.Y {
+		code |= outTop
+	} else if y > r.Y+r.H-1 {
+		code |= outBottom
+	}
+	return code
+}
+
+// ClipLine clips the line from (x1, y1) to (x2, y2) to the pixels of clip
+// with the Cohen–Sutherland algorithm, like clipLine in the C++ QuickCG.
+// It returns the new endpoints, or false if the line lies outside clip.
+func ClipLine(x1, y1, x2, y2 int, clip Rect) (int, int, int, int, bool) {
+	if clip.Empty() {
+		return 0, 0, 0, 0, false
+	}
+
+	xmin, ymin := clip.X, clip.Y
+	xmax, ymax := clip.X+clip.W-1, clip.Y+clip.H-1
+	code1, code2 := outcode(x1, y1, clip), outcode(x2, y2, clip)
+
+	for {
+		if code1|code2 == 0 {
+			return x1, y1, x2, y2, true
+		}
+		if code1&code2 != 0 {
+			return 0, 0, 0, 0, false
+		}
+
+		// Move the endpoint that is outside onto the boundary it crosses.
+		code := code1
+		if code == 0 {
+			code = code2
+		}
+
+		var x, y int
+		switch {
+		case code&outTop != 0:
+			x, y = x1+divRound((x2-x1)*(ymin-y1), y2-y1), ymin
+		case code&outBottom != 0:
+			x, y = x1+divRound((x2-x1)*(ymax-y1), y2-y1), ymax
+		case code&outLeft != 0:
+			x, y = xmin, y1+divRound((y2-y1)*(xmin-x1), x2-x1)
+		default:
+			x, y = xmax, y1+divRound((y2-y1)*(xmax-x1), x2-x1)
+		}
+
+		if code == code1 {
+			x1, y1 = x, y
+			code1 = outcode(x1, y1, clip)
+		} else {
+			x2, y2 = x, y
+			code2 = outcode(x2, y2, clip)
+		}
+	}
+}
+
+// divRound returns a/b rounded to the nearest integer.
+func divRound(a, b int) int {
+	if (a < 0) != (b < 0) {
+		return (a - b/2) / b
+	}
+	return (a + b/2) / b
+}
+
+// ClipLineF clips the line from (x1, y1) to (x2, y2) to the area covered by
+// clip, [X, X+W] x [Y, Y+H], with the Liang–Barsky algorithm. It suits lines
+// with sub-pixel endpoints and returns false if the line lies outside clip.
+func ClipLineF(x1, y1, x2, y2 float64, clip Rect) (float64, float64, float64, float64, bool) {
+	dx, dy := x2-x1, y2-y1
+	t0, t1 := 0.0, 1.0
+
+	// Each boundary as p*t <= q: left, right, top, bottom.
+	p := [4]float64{-dx, dx, -dy, dy}
+	q := [4]float64{
+		x1 - float64(clip.X),
+		float64(clip.X+clip.W) - x1,
+		y1 - float64(clip.Y),
+		float64(clip.Y+clip.H) - y1,
+	}
+
+	for i := range 4 {
+		if p[i] == 0 {
+			if q[i] < 0 {
+				return 0, 0, 0, 0, false
+			}
+			continue
+		}
+		t := q[i] / p[i]
+		if p[i] < 0 {
+			t0 = max(t0, t)
+		} else {
+			t1 = min(t1, t)
+		}
+		if t0 > t1 {
+			return 0, 0, 0, 0, false
+		}
+	}
+
+	return x1 + t0*dx, y1 + t0*dy, x1 + t1*dx, y1 + t1*dy, true
+}
+
+// ClipPolygon clips a polygon to the area covered by clip, [X, X+W] x [Y, Y+H],
+// with the Sutherland–Hodgman algorithm. Convex polygons stay convex; concave
+// ones may gain zero-width edges along the boundary. The result is empty if
+// the polygon lies outside clip.
+func ClipPolygon(points []vecmath.Vec2, clip Rect) []vecmath.Vec2 {
+	x0, y0 := float64(clip.X), float64(clip.Y)
+	x1, y1 := float64(clip.X+clip.W), float64(clip.Y+clip.H)
+
+	// Signed distance to each boundary, positive inside.
+	edges := [4]func(p vecmath.Vec2) float64{
+		func(p vecmath.Vec2) float64 { return p.X - x0 },
+		func(p vecmath.Vec2) float64 { return x1 - p.X },
+		func(p vecmath.Vec2) float64 { return p.Y - y0 },
+		func(p vecmath.Vec2) float64 { return y1 - p.Y },
+	}
+
+	out := points
+	for _, dist := range edges {
+		if len(out) == 0 {
+			break
+		}
+		in := out
+		out = make([]vecmath.Vec2, 0, len(in)+1)
+		for i, cur := range in {
+			prev := in[(i+len(in)-1)%len(in)]
+			dc, dp := dist(cur), dist(prev)
+			if dc >= 0 {
+				if dp < 0 && dc > 0 {
+					out = append(out, prev.Lerp(cur, dp/(dp-dc)))
+				}
+				out = append(out, cur)
+			} else if dp > 0 {
+				out = append(out, prev.Lerp(cur, dp/(dp-dc)))
+			}
+		}
+	}
+
+	return out
+}
+
+// SetClip restricts all drawing on the screen to r, given in drawing
+// coordinates. It is clipped to the screen bounds; pixels outside it are
+// silently skipped. Fill and DrawBuffer still cover the whole screen.
+func (screen *Screen) SetClip(r Rect) {
+	r.X += screen.ox
+	r.Y += screen.oy
+	screen.clip = r.Intersect(screen.bounds())
+}
+
+// ResetClip lets drawing reach the whole screen again.
+func (screen *Screen) ResetClip() {
+	screen.clip = screen.bounds()
+}
+
+// Clip returns the rectangle drawing is currently restricted to, in screen
+// coordinates.
+func (screen *Screen) Clip() Rect {
+	return screen.clip
+}
+
+// PushClip saves the clip rectangle and narrows it to r, given in drawing
+// coordinates. Nested clips intersect, so drawing never leaves an outer one.
+// Restore the previous clip with PopClip.
+func (screen *Screen) PushClip(r Rect) {
+	screen.clipStack = append(screen.clipStack, screen.clip)
+	r.X += screen.ox
+	r.Y += screen.oy
+	screen.clip = r.Intersect(screen.clip)
+}
+
+// PopClip restores the clip rectangle saved by the matching PushClip.
+// It does nothing if no clip was pushed.
+func (screen *Screen) PopClip() {
+	if n := len(screen.clipStack); n > 0 {
+		screen.clip = screen.clipStack[n-1]
+		screen.clipStack = screen.clipStack[:n-1]
+	}
+}
+
+// PushTranslate saves the drawing origin and moves it by (dx, dy), so that
+// drawing at (0, 0) afterwards lands at the old (dx, dy). It applies to all
+// drawing calls, WritePixel included, and to clip rectangles pushed later.
+// Restore the previous origin with Pop.
+func (screen *Screen) PushTranslate(dx, dy int) {
+	screen.originStack = append(screen.originStack, [2]int{screen.ox, screen.oy})
+	screen.ox += dx
+	screen.oy += dy
+}
+
+// Pop restores the drawing origin saved by the matching PushTranslate.
+// It does nothing if no translation was pushed.
+func (screen *Screen) Pop() {
+	if n := len(screen.originStack); n > 0 {
+		screen.ox, screen.oy = screen.originStack[n-1][0], screen.originStack[n-1][1]
+		screen.originStack = screen.originStack[:n-1]
+	}
+}
+
+// Origin returns the screen position that drawing coordinates (0, 0) map to.
+func (screen *Screen) Origin() (int, int) {
+	return screen.ox, screen.oy
+}
+
+// bounds returns the rectangle covering the whole screen.
+func (screen *Screen) bounds() Rect {
+	return Rect{W: screen.w, H: screen.h}
+}
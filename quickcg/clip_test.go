@@ -0,0 +1,129 @@
+package quickcg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+func TestRectIntersect(t *testing.T) {
+	tests := []struct {
+		a, b, want Rect
+	}{
+		{Rect{0, 0, 10, 10}, Rect{2, 3, 4, 5}, Rect{2, 3, 4, 5}},
+		{Rect{0, 0, 10, 10}, Rect{5, -5, 10, 10}, Rect{5, 0, 5, 5}},
+		{Rect{0, 0, 10, 10}, Rect{10, 0, 5, 5}, Rect{}},
+		{Rect{0, 0, 10, 10}, Rect{-20, -20, 5, 5}, Rect{}},
+		{Rect{0, 0, 10, 10}, Rect{3, 3, 0, 4}, Rect{}},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Intersect(tt.b); got != tt.want {
+			t.Errorf("%v.Intersect(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := tt.b.Intersect(tt.a); got != tt.want {
+			t.Errorf("%v.Intersect(%v) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestClipLine(t *testing.T) {
+	clip := Rect{W: 10, H: 10}
+	tests := []struct {
+		name     string
+		in, want [4]int
+		ok       bool
+	}{
+		{"inside", [4]int{2, 3, 7, 8}, [4]int{2, 3, 7, 8}, true},
+		{"reject left", [4]int{-5, -5, -1, 20}, [4]int{}, false},
+		{"reject below", [4]int{0, 12, 9, 15}, [4]int{}, false},
+		{"reject corner", [4]int{-3, 2, 2, -3}, [4]int{}, false},
+		{"diagonal", [4]int{-5, -5, 14, 14}, [4]int{0, 0, 9, 9}, true},
+		{"anti-diagonal", [4]int{12, -3, -3, 12}, [4]int{9, 0, 0, 9}, true},
+		{"horizontal", [4]int{-3, 5, 12, 5}, [4]int{0, 5, 9, 5}, true},
+		{"vertical", [4]int{4, 20, 4, -1}, [4]int{4, 9, 4, 0}, true},
+		{"point inside", [4]int{4, 4, 4, 4}, [4]int{4, 4, 4, 4}, true},
+		{"point outside", [4]int{20, 4, 20, 4}, [4]int{}, false},
+	}
+	for _, tt := range tests {
+		x1, y1, x2, y2, ok := ClipLine(tt.in[0], tt.in[1], tt.in[2], tt.in[3], clip)
+		if got := [4]int{x1, y1, x2, y2}; ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("%s: ClipLine(%v) = %v, %v, want %v, %v", tt.name, tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, _, _, _, ok := ClipLine(0, 0, 5, 5, Rect{}); ok {
+		t.Error("line accepted by an empty clip rectangle")
+	}
+}
+
+func TestClipLineF(t *testing.T) {
+	clip := Rect{W: 10, H: 10}
+	tests := []struct {
+		name     string
+		in, want [4]float64
+		ok       bool
+	}{
+		{"inside", [4]float64{1.5, 2.5, 8.5, 9.5}, [4]float64{1.5, 2.5, 8.5, 9.5}, true},
+		{"reject", [4]float64{-5, -1, 20, -0.5}, [4]float64{}, false},
+		{"reject corner", [4]float64{-3, 2, 2, -3}, [4]float64{}, false},
+		{"diagonal", [4]float64{-5, -5, 15, 15}, [4]float64{0, 0, 10, 10}, true},
+		{"crossing", [4]float64{-2, 4, 12, 11}, [4]float64{0, 5, 10, 10}, true},
+		{"point inside", [4]float64{3, 3, 3, 3}, [4]float64{3, 3, 3, 3}, true},
+		{"point outside", [4]float64{-1, 3, -1, 3}, [4]float64{}, false},
+	}
+	for _, tt := range tests {
+		x1, y1, x2, y2, ok := ClipLineF(tt.in[0], tt.in[1], tt.in[2], tt.in[3], clip)
+		got := [4]float64{x1, y1, x2, y2}
+		if ok != tt.ok {
+			t.Errorf("%s: ClipLineF(%v) ok = %v, want %v", tt.name, tt.in, ok, tt.ok)
+			continue
+		}
+		for i := range got {
+			if ok && math.Abs(got[i]-tt.want[i]) > 1e-9 {
+				t.Errorf("%s: ClipLineF(%v) = %v, want %v", tt.name, tt.in, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestClipPolygon(t *testing.T) {
+	clip := Rect{W: 10, H: 10}
+	square := func(x0, y0, x1, y1 float64) []vecmath.Vec2 {
+		return []vecmath.Vec2{{X: x0, Y: y0}, {X: x1, Y: y0}, {X: x1, Y: y1}, {X: x0, Y: y1}}
+	}
+	area := func(poly []vecmath.Vec2) float64 {
+		a := 0.0
+		for i, p := range poly {
+			a += p.Cross(poly[(i+1)%len(poly)])
+		}
+		return math.Abs(a) / 2
+	}
+
+	inside := square(2, 2, 8, 8)
+	if got := ClipPolygon(inside, clip); len(got) != 4 || area(got) != 36 {
+		t.Errorf("polygon inside changed to %v", got)
+	}
+	if got := ClipPolygon(square(12, 2, 15, 8), clip); len(got) != 0 {
+		t.Errorf("polygon outside clipped to %v, want nothing", got)
+	}
+	if got := ClipPolygon(square(-5, -5, 15, 15), clip); area(got) != 100 {
+		t.Errorf("polygon covering the clip has area %v, want 100", area(got))
+	}
+	if got := ClipPolygon(square(5, 5, 15, 15), clip); len(got) != 4 || area(got) != 25 {
+		t.Errorf("polygon over a corner clipped to %v, want a 5x5 square", got)
+	}
+
+	// A triangle poking out of the left side gains a vertex.
+	tri := []vecmath.Vec2{{X: -4, Y: 5}, {X: 6, Y: 0}, {X: 6, Y: 10}}
+	got := ClipPolygon(tri, clip)
+	if len(got) != 4 || math.Abs(area(got)-(50-8)) > 1e-9 {
+		t.Errorf("triangle clipped to %v with area %v, want 4 vertices and area 42", got, area(got))
+	}
+	for _, p := range got {
+		if p.X < 0 || p.X > 10 || p.Y < 0 || p.Y > 10 {
+			t.Errorf("clipped vertex %v lies outside the clip rectangle", p)
+		}
+	}
+}
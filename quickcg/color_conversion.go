@@ -4,56 +4,115 @@ import (
 	"math"
 )
 
+// Float converts c to float channels in [0,1].
+func (c ColorRGB) Float() ColorF {
+	return ColorF{R: float64(c.R) / 255, G: float64(c.G) / 255, B: float64(c.B) / 255}
+}
+
+// RGB converts c to 8-bit channels, rounding to the nearest value and
+// clamping to [0,255].
+func (c ColorF) RGB() ColorRGB {
+	return ColorRGB{R: clampUint8(c.R * 255), G: clampUint8(c.G * 255), B: clampUint8(c.B * 255)}
+}
+
+// Add returns the channel-wise sum of c and o.
+func (c ColorF) Add(o ColorF) ColorF {
+	return ColorF{R: c.R + o.R, G: c.G + o.G, B: c.B + o.B}
+}
+
+// Sub returns the channel-wise difference of c and o.
+func (c ColorF) Sub(o ColorF) ColorF {
+	return ColorF{R: c.R - o.R, G: c.G - o.G, B: c.B - o.B}
+}
+
+// Mul returns the channel-wise product of c and o, for example to tint c by o.
+func (c ColorF) Mul(o ColorF) ColorF {
+	return ColorF{R: c.R * o.R, G: c.G * o.G, B: c.B * o.B}
+}
+
+// Scale multiplies every channel by f.
+func (c ColorF) Scale(f float64) ColorF {
+	return ColorF{R: c.R * f, G: c.G * f, B: c.B * f}
+}
+
+// Lerp blends from c at t = 0 to o at t = 1.
+func (c ColorF) Lerp(o ColorF, t float64) ColorF {
+	return ColorF{R: c.R + (o.R-c.R)*t, G: c.G + (o.G-c.G)*t, B: c.B + (o.B-c.B)*t}
+}
+
+// Clamp limits every channel to [0,1].
+func (c ColorF) Clamp() ColorF {
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+	return ColorF{R: clamp(c.R), G: clamp(c.G), B: clamp(c.B)}
+}
+
+// hue returns the hue in [0,1) of a color with the given channels, largest
+// channel max and chroma d > 0.
+func hue(r, g, b, max, d float64) float64 {
+	var h float64
+	switch max {
+	case r:
+		h = (g - b) / d
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h /= 6
+	h -= math.Floor(h)
+	if h >= 1 {
+		// A tiny negative hue wraps to exactly 1 in floating point.
+		h = 0
+	}
+	return h
+}
+
 // RGBtoHSL converts an RGB color to the HSL color model.
 func RGBtoHSL(rgb ColorRGB) ColorHSL {
-	r := float64(rgb.R) / 255
-	g := float64(rgb.G) / 255
-	b := float64(rgb.B) / 255
+	return ColorFtoHSL(rgb.Float())
+}
 
-	max := math.Max(r, math.Max(g, b))
-	min := math.Min(r, math.Min(g, b))
+// ColorFtoHSL converts a float RGB color to the HSL color model.
+// Grays get hue 0 and saturation 0.
+func ColorFtoHSL(c ColorF) ColorHSL {
+	max := math.Max(c.R, math.Max(c.G, c.B))
+	min := math.Min(c.R, math.Min(c.G, c.B))
 	l := (max + min) / 2
 
-	var h, s float64
-	if max == min {
-		h = 0
-		s = 0
-	} else {
-		d := max - min
-		s = d / (1 - math.Abs(2*l-1))
-		switch max {
-		case r:
-			h = math.Mod((g-b)/d, 6)
-		case g:
-			h = (b - r) / d + 2
-		case b:
-			h = (r - g) / d + 4
-		}
-		h /= 6
-		if h < 0 {
-			h += 1
-		}
+	d := max - min
+	if d == 0 {
+		return ColorHSL{L: l}
 	}
-	return ColorHSL{H: h, S: s, L: l}
+	s := d / (1 - math.Abs(2*l-1))
+	return ColorHSL{H: hue(c.R, c.G, c.B, max, d), S: s, L: l}
 }
 
-// HSLtoRGB converts an HSL color to the RGB color model.
+// HSLtoRGB converts an HSL color to the RGB color model, rounding to the
+// nearest 8-bit value. Converting an RGB color to HSL and back gives the
+// original color.
 func HSLtoRGB(hsl ColorHSL) ColorRGB {
-	h, s, l := hsl.H, hsl.S, hsl.L
-	var r, g, b float64
+	return HSLtoColorF(hsl).RGB()
+}
+
+// HSLtoColorF converts an HSL color to float RGB. Hues outside [0,1] wrap around.
+func HSLtoColorF(hsl ColorHSL) ColorF {
+	h, s, l := hsl.H-math.Floor(hsl.H), hsl.S, hsl.L
 	if s == 0 {
-		r, g, b = l, l, l
+		return ColorF{R: l, G: l, B: l}
+	}
+
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
 	} else {
-		q := l * (1 + s)
-		if l >= 0.5 {
-			q = l + s - l*s
-		}
-		p := 2*l - q
-		r = hueToRGB(p, q, h+1.0/3.0)
-		g = hueToRGB(p, q, h)
-		b = hueToRGB(p, q, h-1.0/3.0)
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	return ColorF{
+		R: hueToRGB(p, q, h+1.0/3.0),
+		G: hueToRGB(p, q, h),
+		B: hueToRGB(p, q, h-1.0/3.0),
 	}
-	return ColorRGB{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255)}
 }
 
 func hueToRGB(p, q, t float64) float64 {
@@ -70,74 +129,63 @@ func hueToRGB(p, q, t float64) float64 {
 		return q
 	}
 	if t < 2.0/3.0 {
-		return p + (q-p)*(2.0/3.0 - t)*6
+		return p + (q-p)*(2.0/3.0-t)*6
 	}
 	return p
 }
 
 // RGBtoHSV converts an RGB color to the HSV color model.
 func RGBtoHSV(rgb ColorRGB) ColorHSV {
-	r := float64(rgb.R) / 255
-	g := float64(rgb.G) / 255
-	b := float64(rgb.B) / 255
-
-	max := math.Max(r, math.Max(g, b))
-	min := math.Min(r, math.Min(g, b))
-	d := max - min
+	return ColorFtoHSV(rgb.Float())
+}
 
-	v := max
-	var h, s float64
-	if max != 0 {
-		s = d / max
-	} else {
-		s = 0
-		h = 0
-		return ColorHSV{H: h, S: s, V: v}
-	}
+// ColorFtoHSV converts a float RGB color to the HSV color model.
+// Grays get hue 0 and saturation 0.
+func ColorFtoHSV(c ColorF) ColorHSV {
+	max := math.Max(c.R, math.Max(c.G, c.B))
+	min := math.Min(c.R, math.Min(c.G, c.B))
 
-	switch max {
-	case r:
-		h = (g - b) / d
-		if g < b {
-			h += 6
-		}
-	case g:
-		h = (b - r) / d + 2
-	case b:
-		h = (r - g) / d + 4
+	d := max - min
+	if d == 0 {
+		return ColorHSV{V: max}
 	}
-	h /= 6
-	return ColorHSV{H: h, S: s, V: v}
+	return ColorHSV{H: hue(c.R, c.G, c.B, max, d), S: d / max, V: max}
 }
 
-// HSVtoRGB converts an HSV color to the RGB color model.
+// HSVtoRGB converts an HSV color to the RGB color model, rounding to the
+// nearest 8-bit value. Converting an RGB color to HSV and back gives the
+// original color.
 func HSVtoRGB(hsv ColorHSV) ColorRGB {
-	h := hsv.H * 6
+	return HSVtoColorF(hsv).RGB()
+}
+
+// HSVtoColorF converts an HSV color to float RGB. Hues outside [0,1] wrap around.
+func HSVtoColorF(hsv ColorHSV) ColorF {
+	h := (hsv.H - math.Floor(hsv.H)) * 6
 	s := hsv.S
 	v := hsv.V
 
-	i := int(math.Floor(h))
+	i := int(h)
 	f := h - float64(i)
+	if i >= 6 {
+		i, f = 0, 0
+	}
 	p := v * (1 - s)
 	q := v * (1 - f*s)
 	t := v * (1 - (1-f)*s)
 
-	var r, g, b float64
-	switch i % 6 {
+	switch i {
 	case 0:
-		r, g, b = v, t, p
+		return ColorF{R: v, G: t, B: p}
 	case 1:
-		r, g, b = q, v, p
+		return ColorF{R: q, G: v, B: p}
 	case 2:
-		r, g, b = p, v, t
+		return ColorF{R: p, G: v, B: t}
 	case 3:
-		r, g, b = p, q, v
+		return ColorF{R: p, G: q, B: v}
 	case 4:
-		r, g, b = t, p, v
-	case 5:
-		r, g, b = v, p, q
+		return ColorF{R: t, G: p, B: v}
+	default:
+		return ColorF{R: v, G: p, B: q}
 	}
-
-	return ColorRGB{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255)}
 }
-
@@ -0,0 +1,25 @@
+package quickcg
+
+import "testing"
+
+func TestColorConversionRoundTrip(t *testing.T) {
+	failures := 0
+	for v := range 1 << 24 {
+		c := ColorRGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}
+		if got := HSLtoRGB(RGBtoHSL(c)); got != c {
+			if failures < 10 {
+				t.Errorf("HSLtoRGB(RGBtoHSL(%v)) = %v", c, got)
+			}
+			failures++
+		}
+		if got := HSVtoRGB(RGBtoHSV(c)); got != c {
+			if failures < 10 {
+				t.Errorf("HSVtoRGB(RGBtoHSV(%v)) = %v", c, got)
+			}
+			failures++
+		}
+	}
+	if failures > 0 {
+		t.Errorf("%d round trips failed", failures)
+	}
+}
@@ -0,0 +1,319 @@
+package quickcg
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Add returns the channel-wise sum of c and o, saturating at 255.
+func (c ColorRGB) Add(o ColorRGB) ColorRGB {
+	return ColorRGB{
+		R: uint8(min(int(c.R)+int(o.R), 255)),
+		G: uint8(min(int(c.G)+int(o.G), 255)),
+		B: uint8(min(int(c.B)+int(o.B), 255)),
+	}
+}
+
+// Sub returns the channel-wise difference of c and o, saturating at 0.
+func (c ColorRGB) Sub(o ColorRGB) ColorRGB {
+	return ColorRGB{
+		R: uint8(max(int(c.R)-int(o.R), 0)),
+		G: uint8(max(int(c.G)-int(o.G), 0)),
+		B: uint8(max(int(c.B)-int(o.B), 0)),
+	}
+}
+
+// Scale multiplies every channel by f, rounding and clamping to [0,255].
+func (c ColorRGB) Scale(f float64) ColorRGB {
+	return ColorRGB{
+		R: clampUint8(float64(c.R) * f),
+		G: clampUint8(float64(c.G) * f),
+		B: clampUint8(float64(c.B) * f),
+	}
+}
+
+// Lerp blends the sRGB channels from c at t = 0 to o at t = 1.
+func (c ColorRGB) Lerp(o ColorRGB, t float64) ColorRGB {
+	return lerpRGB(c, o, t)
+}
+
+// Mix blends c and o like Lerp but in linear light, the way light actually
+// adds up. Halfway between red and green it gives a bright yellow rather than
+// Lerp's muddy olive.
+func (c ColorRGB) Mix(o ColorRGB, t float64) ColorRGB {
+	a, b := RGBtoLinearRGB(c), RGBtoLinearRGB(o)
+	return LinearRGBtoRGB(ColorLinearRGB{
+		R: a.R + (b.R-a.R)*t,
+		G: a.G + (b.G-a.G)*t,
+		B: a.B + (b.B-a.B)*t,
+	})
+}
+
+// Luminance returns the relative luminance of c in [0,1], as perceived
+// brightness of the light the color emits.
+func (c ColorRGB) Luminance() float64 {
+	return RGBtoXYZ(c).Y
+}
+
+// Grayscale returns the gray with the same luminance as c.
+func (c ColorRGB) Grayscale() ColorRGB {
+	y := c.Luminance()
+	return LinearRGBtoRGB(ColorLinearRGB{R: y, G: y, B: y})
+}
+
+// Invert returns the complementary color 255 - c.
+func (c ColorRGB) Invert() ColorRGB {
+	return ColorRGB{R: 255 - c.R, G: 255 - c.G, B: 255 - c.B}
+}
+
+// Brightness adds delta, in [-1,1], times 255 to every channel.
+func (c ColorRGB) Brightness(delta float64) ColorRGB {
+	d := delta * 255
+	return ColorRGB{
+		R: clampUint8(float64(c.R) + d),
+		G: clampUint8(float64(c.G) + d),
+		B: clampUint8(float64(c.B) + d),
+	}
+}
+
+// Contrast scales the distance of every channel from mid-gray by factor:
+// 0 gives flat gray, 1 leaves c unchanged and larger values add contrast.
+func (c ColorRGB) Contrast(factor float64) ColorRGB {
+	adjust := func(v uint8) uint8 {
+		return clampUint8((float64(v)-127.5)*factor + 127.5)
+	}
+	return ColorRGB{R: adjust(c.R), G: adjust(c.G), B: adjust(c.B)}
+}
+
+// Gamma applies a gamma correction, raising every channel scaled to [0,1]
+// to the power 1/gamma. Values above 1 brighten the midtones and values
+// below 1 darken them; black and white stay unchanged.
+func (c ColorRGB) Gamma(gamma float64) ColorRGB {
+	adjust := func(v uint8) uint8 {
+		return clampUint8(math.Pow(float64(v)/255, 1/gamma) * 255)
+	}
+	return ColorRGB{R: adjust(c.R), G: adjust(c.G), B: adjust(c.B)}
+}
+
+// Hex formats c as "#rrggbb".
+func (c ColorRGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// ParseHex parses a color written as "#rrggbb" or the short form "#rgb".
+// The leading '#' is optional.
+func ParseHex(s string) (ColorRGB, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return ColorRGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return ColorRGB{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	return ColorRGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, nil
+}
+
+// ParseColor parses a hex color, as ParseHex does, or a CSS color name such
+// as "cornflowerblue".
+func ParseColor(s string) (ColorRGB, error) {
+	if c, ok := ColorByName(s); ok {
+		return c, nil
+	}
+	if c, err := ParseHex(s); err == nil {
+		return c, nil
+	}
+	return ColorRGB{}, fmt.Errorf("unknown color %q", s)
+}
+
+// ColorByName looks up one of the 148 CSS named colors, ignoring case.
+func ColorByName(name string) (ColorRGB, bool) {
+	v, ok := cssColors[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return ColorRGB{}, false
+	}
+	return ColorRGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}, true
+}
+
+// RGBA implements color.Color, so a ColorRGB can be passed to the standard
+// image packages. The color is always fully opaque.
+func (c ColorRGB) RGBA() (r, g, b, a uint32) {
+	r = uint32(c.R) * 0x101
+	g = uint32(c.G) * 0x101
+	b = uint32(c.B) * 0x101
+	return r, g, b, 0xffff
+}
+
+// ColorRGBModel converts any color.Color to a ColorRGB.
+var ColorRGBModel = color.ModelFunc(func(c color.Color) color.Color {
+	return ColortoRGB(c)
+})
+
+// ColortoRGB converts a color.Color to a ColorRGB. Translucent colors are
+// composited over black.
+func ColortoRGB(c color.Color) ColorRGB {
+	if rgb, ok := c.(ColorRGB); ok {
+		return rgb
+	}
+	r, g, b, _ := c.RGBA()
+	return ColorRGB{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8)}
+}
+
+// cssColors maps the CSS Color Module Level 4 named colors to 0xRRGGBB.
+var cssColors = map[string]uint32{
+	"aliceblue":            0xf0f8ff,
+	"antiquewhite":         0xfaebd7,
+	"aqua":                 0x00ffff,
+	"aquamarine":           0x7fffd4,
+	"azure":                0xf0ffff,
+	"beige":                0xf5f5dc,
+	"bisque":               0xffe4c4,
+	"black":                0x000000,
+	"blanchedalmond":       0xffebcd,
+	"blue":                 0x0000ff,
+	"blueviolet":           0x8a2be2,
+	"brown":                0xa52a2a,
+	"burlywood":            0xdeb887,
+	"cadetblue":            0x5f9ea0,
+	"chartreuse":           0x7fff00,
+	"chocolate":            0xd2691e,
+	"coral":                0xff7f50,
+	"cornflowerblue":       0x6495ed,
+	"cornsilk":             0xfff8dc,
+	"crimson":              0xdc143c,
+	"cyan":                 0x00ffff,
+	"darkblue":             0x00008b,
+	"darkcyan":             0x008b8b,
+	"darkgoldenrod":        0xb8860b,
+	"darkgray":             0xa9a9a9,
+	"darkgreen":            0x006400,
+	"darkgrey":             0xa9a9a9,
+	"darkkhaki":            0xbdb76b,
+	"darkmagenta":          0x8b008b,
+	"darkolivegreen":       0x556b2f,
+	"darkorange":           0xff8c00,
+	"darkorchid":           0x9932cc,
+	"darkred":              0x8b0000,
+	"darksalmon":           0xe9967a,
+	"darkseagreen":         0x8fbc8f,
+	"darkslateblue":        0x483d8b,
+	"darkslategray":        0x2f4f4f,
+	"darkslategrey":        0x2f4f4f,
+	"darkturquoise":        0x00ced1,
+	"darkviolet":           0x9400d3,
+	"deeppink":             0xff1493,
+	"deepskyblue":          0x00bfff,
+	"dimgray":              0x696969,
+	"dimgrey":              0x696969,
+	"dodgerblue":           0x1e90ff,
+	"firebrick":            0xb22222,
+	"floralwhite":          0xfffaf0,
+	"forestgreen":          0x228b22,
+	"fuchsia":              0xff00ff,
+	"gainsboro":            0xdcdcdc,
+	"ghostwhite":           0xf8f8ff,
+	"gold":                 0xffd700,
+	"goldenrod":            0xdaa520,
+	"gray":                 0x808080,
+	"green":                0x008000,
+	"greenyellow":          0xadff2f,
+	"grey":                 0x808080,
+	"honeydew":             0xf0fff0,
+	"hotpink":              0xff69b4,
+	"indianred":            0xcd5c5c,
+	"indigo":               0x4b0082,
+	"ivory":                0xfffff0,
+	"khaki":                0xf0e68c,
+	"lavender":             0xe6e6fa,
+	"lavenderblush":        0xfff0f5,
+	"lawngreen":            0x7cfc00,
+	"lemonchiffon":         0xfffacd,
+	"lightblue":            0xadd8e6,
+	"lightcoral":           0xf08080,
+	"lightcyan":            0xe0ffff,
+	"lightgoldenrodyellow": 0xfafad2,
+	"lightgray":            0xd3d3d3,
+	"lightgreen":           0x90ee90,
+	"lightgrey":            0xd3d3d3,
+	"lightpink":            0xffb6c1,
+	"lightsalmon":          0xffa07a,
+	"lightseagreen":        0x20b2aa,
+	"lightskyblue":         0x87cefa,
+	"lightslategray":       0x778899,
+	"lightslategrey":       0x778899,
+	"lightsteelblue":       0xb0c4de,
+	"lightyellow":          0xffffe0,
+	"lime":                 0x00ff00,
+	"limegreen":            0x32cd32,
+	"linen":                0xfaf0e6,
+	"magenta":              0xff00ff,
+	"maroon":               0x800000,
+	"mediumaquamarine":     0x66cdaa,
+	"mediumblue":           0x0000cd,
+	"mediumorchid":         0xba55d3,
+	"mediumpurple":         0x9370db,
+	"mediumseagreen":       0x3cb371,
+	"mediumslateblue":      0x7b68ee,
+	"mediumspringgreen":    0x00fa9a,
+	"mediumturquoise":      0x48d1cc,
+	"mediumvioletred":      0xc71585,
+	"midnightblue":         0x191970,
+	"mintcream":            0xf5fffa,
+	"mistyrose":            0xffe4e1,
+	"moccasin":             0xffe4b5,
+	"navajowhite":          0xffdead,
+	"navy":                 0x000080,
+	"oldlace":              0xfdf5e6,
+	"olive":                0x808000,
+	"olivedrab":            0x6b8e23,
+	"orange":               0xffa500,
+	"orangered":            0xff4500,
+	"orchid":               0xda70d6,
+	"palegoldenrod":        0xeee8aa,
+	"palegreen":            0x98fb98,
+	"paleturquoise":        0xafeeee,
+	"palevioletred":        0xdb7093,
+	"papayawhip":           0xffefd5,
+	"peachpuff":            0xffdab9,
+	"peru":                 0xcd853f,
+	"pink":                 0xffc0cb,
+	"plum":                 0xdda0dd,
+	"powderblue":           0xb0e0e6,
+	"purple":               0x800080,
+	"rebeccapurple":        0x663399,
+	"red":                  0xff0000,
+	"rosybrown":            0xbc8f8f,
+	"royalblue":            0x4169e1,
+	"saddlebrown":          0x8b4513,
+	"salmon":               0xfa8072,
+	"sandybrown":           0xf4a460,
+	"seagreen":             0x2e8b57,
+	"seashell":             0xfff5ee,
+	"sienna":               0xa0522d,
+	"silver":               0xc0c0c0,
+	"skyblue":              0x87ceeb,
+	"slateblue":            0x6a5acd,
+	"slategray":            0x708090,
+	"slategrey":            0x708090,
+	"snow":                 0xfffafa,
+	"springgreen":          0x00ff7f,
+	"steelblue":            0x4682b4,
+	"tan":                  0xd2b48c,
+	"teal":                 0x008080,
+	"thistle":              0xd8bfd8,
+	"tomato":               0xff6347,
+	"turquoise":            0x40e0d0,
+	"violet":               0xee82ee,
+	"wheat":                0xf5deb3,
+	"white":                0xffffff,
+	"whitesmoke":           0xf5f5f5,
+	"yellow":               0xffff00,
+	"yellowgreen":          0x9acd32,
+}
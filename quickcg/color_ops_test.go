@@ -0,0 +1,101 @@
+package quickcg
+
+import "testing"
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ColorRGB
+		ok   bool
+	}{
+		{"#ff8000", ColorRGB{R: 255, G: 128}, true},
+		{"#FF8000", ColorRGB{R: 255, G: 128}, true},
+		{"ff8000", ColorRGB{R: 255, G: 128}, true},
+		{"#f80", ColorRGB{R: 255, G: 136}, true},
+		{"0aF", ColorRGB{G: 170, B: 255}, true},
+		{"#000000", ColorRGB{}, true},
+		{"#ffffff", ColorRGB{R: 255, G: 255, B: 255}, true},
+		{"", ColorRGB{}, false},
+		{"#", ColorRGB{}, false},
+		{"#ff80", ColorRGB{}, false},
+		{"#ff80000", ColorRGB{}, false},
+		{"#ff800g", ColorRGB{}, false},
+		{"#xyz", ColorRGB{}, false},
+		{"##ff8000", ColorRGB{}, false},
+		{"#+f8000", ColorRGB{}, false},
+		{"#0x1234", ColorRGB{}, false},
+		{" #ff8000", ColorRGB{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseHex(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseHex(%q) = %v, %v, want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHexRoundTrip(t *testing.T) {
+	for _, c := range []ColorRGB{{}, {R: 1, G: 2, B: 3}, {R: 255, G: 128, B: 16}, {R: 255, G: 255, B: 255}} {
+		got, err := ParseHex(c.Hex())
+		if err != nil || got != c {
+			t.Errorf("ParseHex(%q) = %v, %v, want %v", c.Hex(), got, err, c)
+		}
+	}
+}
+
+func TestColorByName(t *testing.T) {
+	if len(cssColors) != 148 {
+		t.Errorf("got %d CSS colors, want 148", len(cssColors))
+	}
+
+	tests := []struct {
+		name string
+		want ColorRGB
+	}{
+		{"black", ColorRGB{}},
+		{"white", ColorRGB{R: 255, G: 255, B: 255}},
+		{"red", ColorRGB{R: 255}},
+		{"lime", ColorRGB{G: 255}},
+		{"green", ColorRGB{G: 128}},
+		{"cornflowerblue", ColorRGB{R: 100, G: 149, B: 237}},
+		{"rebeccapurple", ColorRGB{R: 102, G: 51, B: 153}},
+		{"CornflowerBlue", ColorRGB{R: 100, G: 149, B: 237}},
+		{" gray ", ColorRGB{R: 128, G: 128, B: 128}},
+		{"grey", ColorRGB{R: 128, G: 128, B: 128}},
+		{"aqua", ColorRGB{G: 255, B: 255}},
+		{"cyan", ColorRGB{G: 255, B: 255}},
+	}
+	for _, tt := range tests {
+		got, ok := ColorByName(tt.name)
+		if !ok || got != tt.want {
+			t.Errorf("ColorByName(%q) = %v, %v, want %v", tt.name, got, ok, tt.want)
+		}
+	}
+
+	for _, name := range []string{"", "notacolor", "light blue", "#ff0000"} {
+		if c, ok := ColorByName(name); ok {
+			t.Errorf("ColorByName(%q) = %v, want not found", name, c)
+		}
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ColorRGB
+		ok   bool
+	}{
+		{"navy", ColorRGB{B: 128}, true},
+		{"#000080", ColorRGB{B: 128}, true},
+		{"00f", ColorRGB{B: 255}, true},
+		{"bad", ColorRGB{R: 0xbb, G: 0xaa, B: 0xdd}, true}, // three hex digits
+		{"nope", ColorRGB{}, false},
+		{"#12345", ColorRGB{}, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseColor(%q) = %v, %v, want %v, ok %v", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
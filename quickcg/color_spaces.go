@@ -0,0 +1,279 @@
+package quickcg
+
+import (
+	"math"
+)
+
+// D65 reference white in CIE XYZ, used by the CIELAB conversions.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+// srgbToLinear removes the sRGB gamma curve from a channel value in [0,1].
+func srgbToLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB applies the sRGB gamma curve to a linear channel value in [0,1].
+func linearToSRGB(c float64) float64 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// RGBtoLinearRGB converts an sRGB color to linear RGB.
+func RGBtoLinearRGB(rgb ColorRGB) ColorLinearRGB {
+	return ColorLinearRGB{
+		R: srgbToLinear(float64(rgb.R) / 255),
+		G: srgbToLinear(float64(rgb.G) / 255),
+		B: srgbToLinear(float64(rgb.B) / 255),
+	}
+}
+
+// LinearRGBtoRGB converts a linear RGB color to sRGB.
+// Channels outside [0,1] are clipped.
+func LinearRGBtoRGB(lin ColorLinearRGB) ColorRGB {
+	encode := func(c float64) uint8 {
+		return clampUint8(linearToSRGB(math.Max(0, math.Min(1, c))) * 255)
+	}
+	return ColorRGB{R: encode(lin.R), G: encode(lin.G), B: encode(lin.B)}
+}
+
+// LinearRGBtoXYZ converts a linear RGB color to CIE XYZ.
+func LinearRGBtoXYZ(lin ColorLinearRGB) ColorXYZ {
+	return ColorXYZ{
+		X: 0.4124564*lin.R + 0.3575761*lin.G + 0.1804375*lin.B,
+		Y: 0.2126729*lin.R + 0.7151522*lin.G + 0.0721750*lin.B,
+		Z: 0.0193339*lin.R + 0.1191920*lin.G + 0.9503041*lin.B,
+	}
+}
+
+// XYZtoLinearRGB converts a CIE XYZ color to linear RGB. Colors outside
+// the sRGB gamut get channels outside [0,1].
+func XYZtoLinearRGB(xyz ColorXYZ) ColorLinearRGB {
+	return ColorLinearRGB{
+		R: 3.2404542*xyz.X - 1.5371385*xyz.Y - 0.4985314*xyz.Z,
+		G: -0.9692660*xyz.X + 1.8760108*xyz.Y + 0.0415560*xyz.Z,
+		B: 0.0556434*xyz.X - 0.2040259*xyz.Y + 1.0572252*xyz.Z,
+	}
+}
+
+// RGBtoXYZ converts an sRGB color to CIE XYZ.
+func RGBtoXYZ(rgb ColorRGB) ColorXYZ {
+	return LinearRGBtoXYZ(RGBtoLinearRGB(rgb))
+}
+
+// XYZtoRGB converts a CIE XYZ color to sRGB, clipping it to the sRGB gamut.
+func XYZtoRGB(xyz ColorXYZ) ColorRGB {
+	return LinearRGBtoRGB(XYZtoLinearRGB(xyz))
+}
+
+// labF is the CIELAB companding function.
+func labF(t float64) float64 {
+	const delta = 6.0 / 29
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29
+}
+
+// labFInv is the inverse of labF.
+func labFInv(t float64) float64 {
+	const delta = 6.0 / 29
+	if t > delta {
+		return t * t * t
+	}
+	return 3 * delta * delta * (t - 4.0/29)
+}
+
+// XYZtoLab converts a CIE XYZ color to CIELAB.
+func XYZtoLab(xyz ColorXYZ) ColorLab {
+	fx := labF(xyz.X / whiteX)
+	fy := labF(xyz.Y / whiteY)
+	fz := labF(xyz.Z / whiteZ)
+	return ColorLab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+// LabtoXYZ converts a CIELAB color to CIE XYZ.
+func LabtoXYZ(lab ColorLab) ColorXYZ {
+	fy := (lab.L + 16) / 116
+	return ColorXYZ{
+		X: whiteX * labFInv(fy+lab.A/500),
+		Y: whiteY * labFInv(fy),
+		Z: whiteZ * labFInv(fy-lab.B/200),
+	}
+}
+
+// RGBtoLab converts an sRGB color to CIELAB.
+func RGBtoLab(rgb ColorRGB) ColorLab {
+	return XYZtoLab(RGBtoXYZ(rgb))
+}
+
+// LabtoRGB converts a CIELAB color to sRGB, clipping it to the sRGB gamut.
+func LabtoRGB(lab ColorLab) ColorRGB {
+	return XYZtoRGB(LabtoXYZ(lab))
+}
+
+// toPolar returns the chroma and hue angle in degrees of the axes a and b.
+func toPolar(a, b float64) (float64, float64) {
+	h := math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return math.Hypot(a, b), h
+}
+
+// fromPolar is the inverse of toPolar.
+func fromPolar(c, h float64) (float64, float64) {
+	s, co := math.Sincos(h * math.Pi / 180)
+	return c * co, c * s
+}
+
+// LabtoLCh converts a CIELAB color to its cylindrical form.
+func LabtoLCh(lab ColorLab) ColorLCh {
+	c, h := toPolar(lab.A, lab.B)
+	return ColorLCh{L: lab.L, C: c, H: h}
+}
+
+// LChtoLab converts a CIE LCh color to CIELAB.
+func LChtoLab(lch ColorLCh) ColorLab {
+	a, b := fromPolar(lch.C, lch.H)
+	return ColorLab{L: lch.L, A: a, B: b}
+}
+
+// RGBtoLCh converts an sRGB color to CIE LCh.
+func RGBtoLCh(rgb ColorRGB) ColorLCh {
+	return LabtoLCh(RGBtoLab(rgb))
+}
+
+// LChtoRGB converts a CIE LCh color to sRGB, clipping it to the sRGB gamut.
+func LChtoRGB(lch ColorLCh) ColorRGB {
+	return LabtoRGB(LChtoLab(lch))
+}
+
+// LinearRGBtoOKLab converts a linear RGB color to OKLab.
+func LinearRGBtoOKLab(lin ColorLinearRGB) ColorOKLab {
+	l := math.Cbrt(0.4122214708*lin.R + 0.5363325363*lin.G + 0.0514459929*lin.B)
+	m := math.Cbrt(0.2119034982*lin.R + 0.6806995451*lin.G + 0.1073969566*lin.B)
+	s := math.Cbrt(0.0883024619*lin.R + 0.2817188376*lin.G + 0.6299787005*lin.B)
+
+	return ColorOKLab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// OKLabtoLinearRGB converts an OKLab color to linear RGB. Colors outside
+// the sRGB gamut get channels outside [0,1].
+func OKLabtoLinearRGB(lab ColorOKLab) ColorLinearRGB {
+	l := lab.L + 0.3963377774*lab.A + 0.2158037573*lab.B
+	m := lab.L - 0.1055613458*lab.A - 0.0638541728*lab.B
+	s := lab.L - 0.0894841775*lab.A - 1.2914855480*lab.B
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	return ColorLinearRGB{
+		R: 4.0767416621*l - 3.3077115913*m + 0.2309699292*s,
+		G: -1.2684380046*l + 2.6097574011*m - 0.3413193965*s,
+		B: -0.0041960863*l - 0.7034186147*m + 1.7076147010*s,
+	}
+}
+
+// RGBtoOKLab converts an sRGB color to OKLab.
+func RGBtoOKLab(rgb ColorRGB) ColorOKLab {
+	return LinearRGBtoOKLab(RGBtoLinearRGB(rgb))
+}
+
+// OKLabtoRGB converts an OKLab color to sRGB, clipping it to the sRGB gamut.
+func OKLabtoRGB(lab ColorOKLab) ColorRGB {
+	return LinearRGBtoRGB(OKLabtoLinearRGB(lab))
+}
+
+// OKLabtoOKLCh converts an OKLab color to its cylindrical form.
+func OKLabtoOKLCh(lab ColorOKLab) ColorOKLCh {
+	c, h := toPolar(lab.A, lab.B)
+	return ColorOKLCh{L: lab.L, C: c, H: h}
+}
+
+// OKLChtoOKLab converts an OKLCh color to OKLab.
+func OKLChtoOKLab(lch ColorOKLCh) ColorOKLab {
+	a, b := fromPolar(lch.C, lch.H)
+	return ColorOKLab{L: lch.L, A: a, B: b}
+}
+
+// RGBtoOKLCh converts an sRGB color to OKLCh.
+func RGBtoOKLCh(rgb ColorRGB) ColorOKLCh {
+	return OKLabtoOKLCh(RGBtoOKLab(rgb))
+}
+
+// OKLChtoRGB converts an OKLCh color to sRGB, clipping it to the sRGB gamut.
+func OKLChtoRGB(lch ColorOKLCh) ColorRGB {
+	return OKLabtoRGB(OKLChtoOKLab(lch))
+}
+
+// DeltaE76 returns the CIE 1976 color difference, the Euclidean distance in
+// CIELAB. A difference around 2.3 is just noticeable.
+func DeltaE76(a, b ColorLab) float64 {
+	return math.Sqrt((a.L-b.L)*(a.L-b.L) + (a.A-b.A)*(a.A-b.A) + (a.B-b.B)*(a.B-b.B))
+}
+
+// DeltaE2000 returns the CIEDE2000 color difference, which corrects DeltaE76
+// for the eye's uneven sensitivity to lightness, chroma and hue.
+func DeltaE2000(a, b ColorLab) float64 {
+	const pow25to7 = 6103515625.0 // 25^7
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	cBar := (math.Hypot(a.A, a.B) + math.Hypot(b.A, b.B)) / 2
+	cBar7 := math.Pow(cBar, 7)
+	g := 0.5 * (1 - math.Sqrt(cBar7/(cBar7+pow25to7)))
+
+	c1, h1 := toPolar((1+g)*a.A, a.B)
+	c2, h2 := toPolar((1+g)*b.A, b.B)
+
+	dL := b.L - a.L
+	dC := c2 - c1
+
+	var dh, hBar float64
+	switch {
+	case c1*c2 == 0:
+		dh, hBar = 0, h1+h2
+	case math.Abs(h2-h1) <= 180:
+		dh, hBar = h2-h1, (h1+h2)/2
+	case h2-h1 > 180:
+		dh, hBar = h2-h1-360, (h1+h2+360)/2
+	default:
+		dh, hBar = h2-h1+360, (h1+h2+360)/2
+	}
+	if hBar >= 360 {
+		hBar -= 360
+	}
+	dH := 2 * math.Sqrt(c1*c2) * math.Sin(rad(dh)/2)
+
+	lBar := (a.L + b.L) / 2
+	cBarP := (c1 + c2) / 2
+	cBarP7 := math.Pow(cBarP, 7)
+
+	t := 1 - 0.17*math.Cos(rad(hBar-30)) + 0.24*math.Cos(rad(2*hBar)) +
+		0.32*math.Cos(rad(3*hBar+6)) - 0.20*math.Cos(rad(4*hBar-63))
+	dTheta := 30 * math.Exp(-((hBar-275)/25)*((hBar-275)/25))
+	rC := 2 * math.Sqrt(cBarP7/(cBarP7+pow25to7))
+	sL := 1 + 0.015*(lBar-50)*(lBar-50)/math.Sqrt(20+(lBar-50)*(lBar-50))
+	sC := 1 + 0.045*cBarP
+	sH := 1 + 0.015*cBarP*t
+	rT := -math.Sin(rad(2*dTheta)) * rC
+
+	l, c, h := dL/sL, dC/sC, dH/sH
+	return math.Sqrt(l*l + c*c + h*h + rT*c*h)
+}
+
+// DeltaEOK returns the Euclidean distance in OKLab, a cheap perceptual
+// difference. Values around 0.02 are just noticeable.
+func DeltaEOK(a, b ColorOKLab) float64 {
+	return math.Sqrt((a.L-b.L)*(a.L-b.L) + (a.A-b.A)*(a.A-b.A) + (a.B-b.B)*(a.B-b.B))
+}
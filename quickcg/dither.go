@@ -0,0 +1,287 @@
+package quickcg
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+)
+
+// Dither selects how MapToPalette spreads the error between a color and its
+// nearest palette entry over neighbouring pixels.
+type Dither int
+
+const (
+	// DitherNone maps every pixel to its nearest palette entry.
+	DitherNone Dither = iota
+	// DitherBayer adds an 8x8 ordered threshold pattern before mapping.
+	// It is fast, stable between frames and gives a regular crosshatch look.
+	DitherBayer
+	// DitherFloydSteinberg diffuses the whole error to the next pixel and the
+	// row below.
+	DitherFloydSteinberg
+	// DitherAtkinson diffuses three quarters of the error over a wider area,
+	// which keeps more contrast at the cost of losing detail in dark and
+	// bright areas, as on the classic Macintosh.
+	DitherAtkinson
+	// DitherBlueNoise adds a 64x64 blue-noise threshold pattern before
+	// mapping. Like DitherBayer it is stable between frames, but without a
+	// visible grid.
+	DitherBlueNoise
+)
+
+// errorKernel lists the neighbours an error-diffusion dither passes error to,
+// as (dx, dy, weight).
+type errorKernel []struct {
+	dx, dy int
+	w      float64
+}
+
+var (
+	floydSteinbergKernel = errorKernel{
+		{1, 0, 7.0 / 16}, {-1, 1, 3.0 / 16}, {0, 1, 5.0 / 16}, {1, 1, 1.0 / 16},
+	}
+	atkinsonKernel = errorKernel{
+		{1, 0, 1.0 / 8}, {2, 0, 1.0 / 8},
+		{-1, 1, 1.0 / 8}, {0, 1, 1.0 / 8}, {1, 1, 1.0 / 8},
+		{0, 2, 1.0 / 8},
+	}
+)
+
+// MapToPalette returns, for every pixel of img, the index of the palette entry
+// it is drawn with. The palette may hold at most 256 colors.
+func MapToPalette(img *Image, palette Palette, dither Dither) []uint8 {
+	indices := make([]uint8, len(img.Pixels))
+	if len(palette) == 0 {
+		return indices
+	}
+	m := newPaletteMapper(palette)
+
+	switch dither {
+	case DitherFloydSteinberg:
+		diffuseError(img, m, floydSteinbergKernel, indices)
+	case DitherAtkinson:
+		diffuseError(img, m, atkinsonKernel, indices)
+	case DitherBayer, DitherBlueNoise:
+		threshold, size := bayerMatrix(), 8
+		if dither == DitherBlueNoise {
+			threshold, size = blueNoiseMatrix(), blueNoiseSize
+		}
+		// Spread the offsets over about one step between palette colors,
+		// estimated as if the palette were an evenly spaced color cube.
+		spread := 255 / math.Cbrt(float64(len(palette)))
+		for y := range img.H {
+			for x := range img.W {
+				i := y*img.W + x
+				c := img.Pixels[i]
+				d := (threshold[(y%size)*size+x%size] - 0.5) * spread
+				indices[i] = m.index(ColorRGB{
+					R: clampUint8(float64(c.R) + d),
+					G: clampUint8(float64(c.G) + d),
+					B: clampUint8(float64(c.B) + d),
+				})
+			}
+		}
+	default:
+		for i, c := range img.Pixels {
+			indices[i] = m.index(c)
+		}
+	}
+
+	return indices
+}
+
+// diffuseError maps img to palette indices left to right and top to bottom,
+// passing each pixel's error on to its neighbours as given by kernel.
+func diffuseError(img *Image, m *paletteMapper, kernel errorKernel, indices []uint8) {
+	// rows holds the accumulated error of the current row and the rows below
+	// it, with a margin of two pixels on either side.
+	depth := 1
+	for _, k := range kernel {
+		depth = max(depth, k.dy+1)
+	}
+	rows := make([][][3]float64, depth)
+	for i := range rows {
+		rows[i] = make([][3]float64, img.W+4)
+	}
+
+	for y := range img.H {
+		for x := range img.W {
+			i := y*img.W + x
+			c := img.Pixels[i]
+			e := rows[0][x+2]
+			want := ColorRGB{
+				R: clampUint8(float64(c.R) + e[0]),
+				G: clampUint8(float64(c.G) + e[1]),
+				B: clampUint8(float64(c.B) + e[2]),
+			}
+			idx := m.index(want)
+			indices[i] = idx
+
+			got := m.palette[idx]
+			diff := [3]float64{
+				float64(want.R) - float64(got.R),
+				float64(want.G) - float64(got.G),
+				float64(want.B) - float64(got.B),
+			}
+			for _, k := range kernel {
+				row := rows[k.dy]
+				for ch := range 3 {
+					row[x+2+k.dx][ch] += diff[ch] * k.w
+				}
+			}
+		}
+
+		first := rows[0]
+		copy(rows, rows[1:])
+		clear(first)
+		rows[depth-1] = first
+	}
+}
+
+// ApplyPalette replaces every pixel of the image with the palette color
+// MapToPalette chooses for it.
+func (img *Image) ApplyPalette(palette Palette, dither Dither) {
+	if len(palette) == 0 {
+		return
+	}
+	for i, idx := range MapToPalette(img, palette, dither) {
+		img.Pixels[i] = palette[idx]
+	}
+}
+
+// ApplyPalette reduces the screen buffer to the colors of palette, like
+// Image.ApplyPalette. Call DrawBuffer to show it.
+func (screen *Screen) ApplyPalette(palette Palette, dither Dither) {
+	screen.BufferImage().ApplyPalette(palette, dither)
+}
+
+// bayerMatrix returns the 8x8 Bayer threshold matrix with values in (0, 1).
+func bayerMatrix() []float64 {
+	m := make([]float64, 64)
+	for y := range 8 {
+		for x := range 8 {
+			// Interleave the bits of x^y and y, most significant level last.
+			v, xy := 0, x^y
+			for bit := range 3 {
+				v |= (xy>>bit&1)<<(5-2*bit) | (y>>bit&1)<<(4-2*bit)
+			}
+			m[y*8+x] = (float64(v) + 0.5) / 64
+		}
+	}
+	return m
+}
+
+const blueNoiseSize = 64
+
+var (
+	blueNoiseOnce sync.Once
+	blueNoise     []float64
+)
+
+// blueNoiseMatrix returns a tileable blueNoiseSize x blueNoiseSize blue-noise
+// threshold matrix with values in (0, 1). It is generated on first use with
+// Ulichney's void-and-cluster method and a fixed seed, so it is the same in
+// every run.
+func blueNoiseMatrix() []float64 {
+	blueNoiseOnce.Do(func() {
+		blueNoise = voidAndCluster(blueNoiseSize, 1.5, 1)
+	})
+	return blueNoise
+}
+
+// voidAndCluster ranks the cells of a size x size torus so that every prefix
+// of the ranking is spread out as evenly as possible, and returns the ranks
+// scaled to (0, 1).
+func voidAndCluster(size int, sigma float64, seed int64) []float64 {
+	n := size * size
+
+	// kernel[dy*size+dx] is the Gaussian weight of a cell at toroidal offset (dx, dy).
+	kernel := make([]float64, n)
+	for dy := range size {
+		for dx := range size {
+			wx := float64(min(dx, size-dx))
+			wy := float64(min(dy, size-dy))
+			kernel[dy*size+dx] = math.Exp(-(wx*wx + wy*wy) / (2 * sigma * sigma))
+		}
+	}
+
+	// energy holds, for every cell, the summed kernel weights of all set cells.
+	set := make([]bool, n)
+	energy := make([]float64, n)
+	toggle := func(p int, on bool) {
+		set[p] = on
+		sign := 1.0
+		if !on {
+			sign = -1
+		}
+		px, py := p%size, p/size
+		for y := range size {
+			dy := (y - py + size) % size
+			for x := range size {
+				dx := (x - px + size) % size
+				energy[y*size+x] += sign * kernel[dy*size+dx]
+			}
+		}
+	}
+	// tightestCluster finds the set cell with the most set neighbours and
+	// largestVoid the empty cell with the fewest.
+	tightestCluster := func() int {
+		best := -1
+		for p := range n {
+			if set[p] && (best < 0 || energy[p] > energy[best]) {
+				best = p
+			}
+		}
+		return best
+	}
+	largestVoid := func() int {
+		best := -1
+		for p := range n {
+			if !set[p] && (best < 0 || energy[p] < energy[best]) {
+				best = p
+			}
+		}
+		return best
+	}
+
+	// Start from a random pattern covering a tenth of the cells and move
+	// points from clusters into voids until that no longer changes anything.
+	rng := rand.New(rand.NewSource(seed))
+	ones := n / 10
+	for _, p := range rng.Perm(n)[:ones] {
+		toggle(p, true)
+	}
+	for {
+		cluster := tightestCluster()
+		toggle(cluster, false)
+		void := largestVoid()
+		toggle(void, true)
+		if void == cluster {
+			break
+		}
+	}
+	prototype := append([]bool(nil), set...)
+	protoEnergy := append([]float64(nil), energy...)
+
+	rank := make([]int, n)
+	// Rank the prototype's points by removing the tightest cluster each time...
+	for r := ones - 1; r >= 0; r-- {
+		p := tightestCluster()
+		toggle(p, false)
+		rank[p] = r
+	}
+	// ...and the remaining cells by filling the largest void each time.
+	copy(set, prototype)
+	copy(energy, protoEnergy)
+	for r := ones; r < n; r++ {
+		p := largestVoid()
+		toggle(p, true)
+		rank[p] = r
+	}
+
+	matrix := make([]float64, n)
+	for p, r := range rank {
+		matrix[p] = (float64(r) + 0.5) / float64(n)
+	}
+	return matrix
+}
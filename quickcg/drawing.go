@@ -13,10 +13,20 @@ import (
 )
 
 // PSet sets the pixel at (x, y) to the given RGB color.
+// Pixels outside the clip rectangle (see SetClip) are silently skipped.
 func (screen *Screen) PSet(x, y int, color ColorRGB) error {
-	if x < 0 || y < 0 || x >= screen.w || y >= screen.h {
-		err := fmt.Errorf("Can't place pixel out of window bounds!")
-		return err
+	return screen.pset(x+screen.ox, y+screen.oy, color)
+}
+
+// pset is PSet in screen coordinates, ignoring the drawing origin.
+func (screen *Screen) pset(x, y int, color ColorRGB) error {
+	if !screen.clip.Contains(x, y) {
+		return nil
+	}
+
+	if screen.headless() {
+		screen.markPixel(x, y, color)
+		return nil
 	}
 
 	var err error
@@ -33,6 +43,8 @@ func (screen *Screen) PSet(x, y int, color ColorRGB) error {
 		return err
 	}
 
+	screen.markPixel(x, y, color)
+
 	return nil
 }
 
@@ -40,9 +52,11 @@ func (screen *Screen) PSet(x, y int, color ColorRGB) error {
 // Unlike PSet, it does not draw immediately to the screen. To display changes,
 // call DrawBuffer after all pixel writes.
 // 
-// Coordinates outside the screen bounds are silently ignored.
+// Coordinates outside the clip rectangle are silently ignored.
 func (screen *Screen) WritePixel(x, y int, color ColorRGB) {
-	if x < 0 || y < 0 || x >= screen.w || y >= screen.h {
+	x += screen.ox
+	y += screen.oy
+	if !screen.clip.Contains(x, y) {
 		return
 	}
 	screen.buffer[y*screen.w+x] = color
@@ -54,7 +68,19 @@ func (screen *Screen) WritePixel(x, y int, color ColorRGB) {
 //
 // This method is significantly faster than using individual PSet calls
 // and should be preferred for drawing large numbers of pixels.
+//
+// In indexed mode the index buffer is first resolved through the palette
+// into the pixel buffer.
 func (scr *Screen) DrawBuffer() error {
+	if scr.indexed {
+		scr.resolveIndices()
+	}
+
+	if scr.headless() {
+		scr.resetFrame(scr.buffer)
+		return nil
+	}
+
 	pf, err := sdl.AllocFormat(sdl.PIXELFORMAT_RGBA8888)
 	if err != nil {
 		return fmt.Errorf("failed to allocate pixel format: %w", err)
@@ -85,11 +111,18 @@ func (scr *Screen) DrawBuffer() error {
 		return fmt.Errorf("error copying texture: %w", err)
 	}
 
+	scr.resetFrame(scr.buffer)
+
 	return nil
 }
 
 // Fill fills the screen with the specified RGB color.
 func (screen *Screen) Fill(color ColorRGB) error {
+	if screen.headless() {
+		screen.clearFrame(color)
+		return nil
+	}
+
 	var err error
 
 	err = screen.renderer.SetDrawColor(color.R, color.G, color.B, 255)
@@ -104,16 +137,33 @@ func (screen *Screen) Fill(color ColorRGB) error {
 		return err
 	}
 
+	screen.clearFrame(color)
+
 	return nil
 }
 
 // Redraw updates the display with any changes made since the last call.
 func (screen *Screen) Redraw() {
-	screen.renderer.Present()
+	if !screen.headless() {
+		screen.renderer.Present()
+	}
+	screen.presentFrame()
+	screen.recordFrame()
 }
 
 // DrawLine draws a line between two points with the specified color.
+// The line is clipped to the clip rectangle.
 func (screen *Screen) DrawLine(x1, y1, x2, y2 int, color ColorRGB) error {
+	x1, y1, x2, y2, ok := ClipLine(x1+screen.ox, y1+screen.oy, x2+screen.ox, y2+screen.oy, screen.clip)
+	if !ok {
+		return nil
+	}
+
+	if screen.headless() {
+		screen.markLine(x1, y1, x2, y2, color)
+		return nil
+	}
+
 	var err error
 
 	err = screen.renderer.SetDrawColor(color.R, color.G, color.B, 255)
@@ -128,17 +178,32 @@ func (screen *Screen) DrawLine(x1, y1, x2, y2 int, color ColorRGB) error {
 		return err
 	}
 
+	screen.markLine(x1, y1, x2, y2, color)
+
 	return nil
 }
 
 // DrawRect draws the outline of a rectangle.
+// The rectangle is clipped to the clip rectangle.
 func (screen *Screen) DrawRect(x1, y1, x2, y2 int, color ColorRGB) error {
+	r, ok := ClipRect(RectFromCorners(x1+screen.ox, y1+screen.oy, x2+screen.ox, y2+screen.oy), screen.clip)
+	if !ok {
+		return nil
+	}
+	x1, y1, x2, y2 = r.X, r.Y, r.X+r.W, r.Y+r.H
+
 	rect := sdl.Rect{
 		X: int32(x1),
 		Y: int32(y1),
 		W: int32(x2 - x1),
 		H: int32(y2 - y1),
 	}
+
+	if screen.headless() {
+		screen.markRect(x1, y1, x2, y2, color)
+		return nil
+	}
+
 	var err error
 	err = screen.renderer.SetDrawColor(color.R, color.G, color.B, 255)
 	if err != nil {
@@ -152,10 +217,13 @@ func (screen *Screen) DrawRect(x1, y1, x2, y2 int, color ColorRGB) error {
 		return err
 	}
 
+	screen.markRect(x1, y1, x2, y2, color)
+
 	return nil
 }
 
 // DrawCircle draws the outline of a circle.
+// Points outside the clip rectangle are skipped.
 func (screen *Screen) DrawCircle(xc, yc, radius int, color ColorRGB) error {
 	x := 0
 	y := radius
@@ -186,11 +254,15 @@ func (screen *Screen) DrawCircle(xc, yc, radius int, color ColorRGB) error {
 }
 
 // DrawFilledCircle draws a filled circle centered at (xc, yc) with radius r.
+// Points outside the clip rectangle are skipped.
 func (screen *Screen) DrawFilledCircle(xc, yc, radius int, color ColorRGB) error {
-	for y := -radius; y <= radius; y++ {
-		for x := -radius; x <= radius; x++ {
+	xc += screen.ox
+	yc += screen.oy
+	c := screen.clip
+	for y := max(-radius, c.Y-yc); y <= min(radius, c.Y+c.H-1-yc); y++ {
+		for x := max(-radius, c.X-xc); x <= min(radius, c.X+c.W-1-xc); x++ {
 			if x*x+y*y <= radius*radius {
-				err := screen.PSet(xc+x, yc+y, color)
+				err := screen.pset(xc+x, yc+y, color)
 				if err != nil {
 					return err
 				}
@@ -209,15 +281,16 @@ func (screen *Screen) DrawText(x, y int, text string, color ColorRGB) error {
 		Dst:  img,
 		Src:  image.NewUniform(col),
 		Face: basicfont.Face7x13,
-		Dot:  fixed.Point26_6{X: fixed.I(x), Y: fixed.I(y)},
+		Dot:  fixed.Point26_6{X: fixed.I(x + screen.ox), Y: fixed.I(y + screen.oy)},
 	}
 	d.DrawString(text)
-	for py := range screen.h {
-		for px := range screen.w {
+	c := screen.clip
+	for py := c.Y; py < c.Y+c.H; py++ {
+		for px := c.X; px < c.X+c.W; px++ {
 			clr := img.At(px, py)
 			r, g, b, a := clr.RGBA()
 			if a > 0 {
-				err := screen.PSet(px, py, ColorRGB{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)})
+				err := screen.pset(px, py, ColorRGB{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8)})
 				if err != nil {
 					return err
 				}
@@ -228,11 +301,19 @@ func (screen *Screen) DrawText(x, y int, text string, color ColorRGB) error {
 }
 
 // DrawImage draws a preloaded image pixel buffer at the given screen position.
+// The image is clipped to the clip rectangle.
 func (screen *Screen) DrawImage(pixels []ColorRGB, imgW, imgH, posX, posY int) error {
-	for y := range imgH {
-		for x := range imgW {
-			color := pixels[y*imgW+x]
-			err := screen.PSet(posX+x, posY+y, color)
+	posX += screen.ox
+	posY += screen.oy
+	r, ok := ClipRect(Rect{X: posX, Y: posY, W: imgW, H: imgH}, screen.clip)
+	if !ok {
+		return nil
+	}
+
+	for y := r.Y; y < r.Y+r.H; y++ {
+		for x := r.X; x < r.X+r.W; x++ {
+			color := pixels[(y-posY)*imgW+(x-posX)]
+			err := screen.pset(x, y, color)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,129 @@
+package quickcg
+
+// FillOptions configures FloodFill and BoundaryFill.
+type FillOptions struct {
+	// Diagonal makes pixels that touch only at a corner neighbors
+	// (8-connectivity). By default only edge neighbors count (4-connectivity).
+	Diagonal bool
+	// Tolerance is the largest difference per channel, 0-255, for which two
+	// colors still count as equal.
+	Tolerance int
+}
+
+// FloodFill replaces the region of pixels connected to (x, y) that share
+// its color, within the tolerance, with color.
+func (img *Image) FloodFill(x, y int, color ColorRGB, opts FillOptions) {
+	if !img.bounds().Contains(x, y) {
+		return
+	}
+	seed := img.Pixels[y*img.W+x]
+	img.fill(x, y, img.bounds(), color, opts, func(c ColorRGB) bool {
+		return colorsClose(c, seed, opts.Tolerance)
+	})
+}
+
+// BoundaryFill paints the region around (x, y) with fill, spreading until it
+// reaches pixels of the border color, within the tolerance.
+func (img *Image) BoundaryFill(x, y int, fill, border ColorRGB, opts FillOptions) {
+	img.fill(x, y, img.bounds(), fill, opts, func(c ColorRGB) bool {
+		return !colorsClose(c, border, opts.Tolerance)
+	})
+}
+
+// FloodFill flood-fills the screen buffer from (x, y), like Image.FloodFill.
+// The fill stays inside the clip rectangle. Call DrawBuffer to show it.
+func (screen *Screen) FloodFill(x, y int, color ColorRGB, opts FillOptions) {
+	x += screen.ox
+	y += screen.oy
+	if !screen.clip.Contains(x, y) {
+		return
+	}
+	img := screen.BufferImage()
+	seed := img.Pixels[y*img.W+x]
+	img.fill(x, y, screen.clip, color, opts, func(c ColorRGB) bool {
+		return colorsClose(c, seed, opts.Tolerance)
+	})
+}
+
+// BoundaryFill boundary-fills the screen buffer from (x, y), like
+// Image.BoundaryFill. The fill stays inside the clip rectangle.
+// Call DrawBuffer to show it.
+func (screen *Screen) BoundaryFill(x, y int, fill, border ColorRGB, opts FillOptions) {
+	screen.BufferImage().fill(x+screen.ox, y+screen.oy, screen.clip, fill, opts, func(c ColorRGB) bool {
+		return !colorsClose(c, border, opts.Tolerance)
+	})
+}
+
+// fill paints the pixels inside area that are connected to (x, y) and for
+// which inside returns true. It fills one horizontal span at a time and keeps
+// the spans still to visit on an explicit stack instead of recursing.
+func (img *Image) fill(x, y int, area Rect, color ColorRGB, opts FillOptions, inside func(ColorRGB) bool) {
+	area = area.Intersect(img.bounds())
+	if !area.Contains(x, y) {
+		return
+	}
+
+	// The visited mask keeps the fill from revisiting pixels whose new
+	// color still matches, such as a flood fill within the tolerance.
+	visited := make([]bool, area.W*area.H)
+	match := func(x, y int) bool {
+		if !area.Contains(x, y) || visited[(y-area.Y)*area.W+x-area.X] {
+			return false
+		}
+		return inside(img.Pixels[y*img.W+x])
+	}
+
+	reach := 0
+	if opts.Diagonal {
+		reach = 1
+	}
+
+	stack := [][2]int{{x, y}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !match(p[0], p[1]) {
+			continue
+		}
+
+		// Grow the span to the left and right of the seed.
+		left, right, y := p[0], p[0], p[1]
+		for match(left-1, y) {
+			left--
+		}
+		for match(right+1, y) {
+			right++
+		}
+		for x := left; x <= right; x++ {
+			visited[(y-area.Y)*area.W+x-area.X] = true
+			img.Pixels[y*img.W+x] = color
+		}
+
+		// Seed one pixel of every run that continues the span above or below.
+		for _, ny := range [2]int{y - 1, y + 1} {
+			running := false
+			for x := left - reach; x <= right+reach; x++ {
+				if match(x, ny) {
+					if !running {
+						stack = append(stack, [2]int{x, ny})
+						running = true
+					}
+				} else {
+					running = false
+				}
+			}
+		}
+	}
+}
+
+// bounds returns the rectangle covering the whole image.
+func (img *Image) bounds() Rect {
+	return Rect{W: img.W, H: img.H}
+}
+
+// colorsClose reports whether no channel of a and b differs by more than tolerance.
+func colorsClose(a, b ColorRGB, tolerance int) bool {
+	return abs(int(a.R)-int(b.R)) <= tolerance &&
+		abs(int(a.G)-int(b.G)) <= tolerance &&
+		abs(int(a.B)-int(b.B)) <= tolerance
+}
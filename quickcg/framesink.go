@@ -0,0 +1,156 @@
+package quickcg
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FrameFormat selects how a FrameSink stores frames.
+type FrameFormat int
+
+const (
+	// FramePNGSequence writes every frame to its own numbered PNG file.
+	FramePNGSequence FrameFormat = iota
+	// FrameY4M writes all frames to a single uncompressed YUV4MPEG2 stream.
+	FrameY4M
+)
+
+// FrameSink writes rendered frames to disk at a fixed virtual frame rate.
+//
+// The sink does not look at the wall clock: every written frame advances
+// its clock by exactly 1/fps seconds, so a render that takes minutes per
+// frame still produces a movie that plays back at the requested rate.
+// Use Time to drive animations from the virtual clock.
+type FrameSink struct {
+	format FrameFormat
+	path   string
+	fps    int
+	frame  int
+	w, h   int
+	file   *os.File
+	writer *bufio.Writer
+	planes []byte
+}
+
+// NewFrameSink creates a frame sink writing at fps frames per second.
+//
+// For FramePNGSequence, path is a printf pattern receiving the frame number,
+// e.g. "frames/zoom_%05d.png". For FrameY4M, path is the output file, which
+// external tools such as ffmpeg can encode later.
+func NewFrameSink(path string, format FrameFormat, fps int) (*FrameSink, error) {
+	if fps <= 0 {
+		return nil, fmt.Errorf("frame rate must be positive, got %d", fps)
+	}
+
+	sink := &FrameSink{format: format, path: path, fps: fps}
+
+	switch format {
+	case FramePNGSequence:
+		if !strings.Contains(path, "%") {
+			return nil, fmt.Errorf("PNG sequence path %q needs a frame number verb such as %%05d", path)
+		}
+	case FrameY4M:
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create file: %w", err)
+		}
+		sink.file = file
+		sink.writer = bufio.NewWriter(file)
+	default:
+		return nil, fmt.Errorf("unknown frame format %d", format)
+	}
+
+	return sink, nil
+}
+
+// Frame returns the number of frames written so far.
+func (sink *FrameSink) Frame() int {
+	return sink.frame
+}
+
+// Time returns the virtual time in seconds at which the next frame is shown.
+func (sink *FrameSink) Time() float64 {
+	return float64(sink.frame) / float64(sink.fps)
+}
+
+// WriteScreen writes the screen buffer as the next frame.
+func (sink *FrameSink) WriteScreen(screen *Screen) error {
+	return sink.WriteImage(screen.BufferImage())
+}
+
+// WriteImage writes img as the next frame.
+// All frames of a Y4M stream must have the same size.
+func (sink *FrameSink) WriteImage(img *Image) error {
+	var err error
+
+	switch sink.format {
+	case FramePNGSequence:
+		err = img.SavePNG(fmt.Sprintf(sink.path, sink.frame))
+	case FrameY4M:
+		err = sink.writeY4M(img)
+	}
+	if err != nil {
+		return err
+	}
+
+	sink.frame++
+	return nil
+}
+
+// Close flushes and closes the output.
+func (sink *FrameSink) Close() error {
+	if sink.file == nil {
+		return nil
+	}
+
+	err := sink.writer.Flush()
+	if err != nil {
+		sink.file.Close()
+		return fmt.Errorf("failed to flush frames: %w", err)
+	}
+
+	err = sink.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	return nil
+}
+
+// writeY4M appends img to the stream as a 4:4:4 frame in BT.601 limited range.
+// The stream header is written before the first frame, once the size is known.
+func (sink *FrameSink) writeY4M(img *Image) error {
+	if sink.frame == 0 {
+		sink.w, sink.h = img.W, img.H
+		sink.planes = make([]byte, img.W*img.H*3)
+		_, err := fmt.Fprintf(sink.writer, "YUV4MPEG2 W%d H%d F%d:1 Ip A1:1 C444 XCOLORRANGE=LIMITED\n", img.W, img.H, sink.fps)
+		if err != nil {
+			return fmt.Errorf("failed to write stream header: %w", err)
+		}
+	} else if img.W != sink.w || img.H != sink.h {
+		return fmt.Errorf("frame size %dx%d does not match stream size %dx%d", img.W, img.H, sink.w, sink.h)
+	}
+
+	n := img.W * img.H
+	yPlane := sink.planes[:n]
+	uPlane := sink.planes[n : 2*n]
+	vPlane := sink.planes[2*n:]
+	for i, c := range img.Pixels {
+		r, g, b := float64(c.R), float64(c.G), float64(c.B)
+		yPlane[i] = clampUint8(16 + 0.256788*r + 0.504129*g + 0.097906*b)
+		uPlane[i] = clampUint8(128 - 0.148223*r - 0.290993*g + 0.439216*b)
+		vPlane[i] = clampUint8(128 + 0.439216*r - 0.367788*g - 0.071427*b)
+	}
+
+	_, err := sink.writer.WriteString("FRAME\n")
+	if err == nil {
+		_, err = sink.writer.Write(sink.planes)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write frame: %w", err)
+	}
+
+	return nil
+}
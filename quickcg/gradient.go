@@ -0,0 +1,306 @@
+package quickcg
+
+import (
+	"math"
+	"sort"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// Colormap maps a value t, usually in [0,1], to a color.
+type Colormap interface {
+	At(t float64) ColorRGB
+}
+
+// Interpolation selects the color space a Gradient blends its stops in.
+type Interpolation int
+
+const (
+	// InterpolateRGB blends the sRGB channels directly.
+	InterpolateRGB Interpolation = iota
+	// InterpolateHSL blends hue along the shorter way around the color wheel,
+	// which keeps saturated colors bright between stops.
+	InterpolateHSL
+	// InterpolateOKLab blends in OKLab, which spaces the colors evenly to the eye.
+	InterpolateOKLab
+)
+
+// GradientStop is a color at position Pos in [0,1] of a Gradient.
+type GradientStop struct {
+	Pos   float64
+	Color ColorRGB
+}
+
+// Gradient blends between color stops.
+type Gradient struct {
+	Stops         []GradientStop // sorted by Pos
+	Interpolation Interpolation
+	// Cyclic makes the gradient repeat: At wraps t into [0,1) and blends
+	// from the last stop back to the first.
+	Cyclic bool
+}
+
+// NewGradient creates a gradient with the colors spread evenly over [0,1].
+func NewGradient(mode Interpolation, colors ...ColorRGB) *Gradient {
+	g := &Gradient{Interpolation: mode}
+	for i, c := range colors {
+		pos := 0.0
+		if len(colors) > 1 {
+			pos = float64(i) / float64(len(colors)-1)
+		}
+		g.Stops = append(g.Stops, GradientStop{Pos: pos, Color: c})
+	}
+	return g
+}
+
+// NewGradientStops creates a gradient from stops at arbitrary positions.
+func NewGradientStops(mode Interpolation, stops ...GradientStop) *Gradient {
+	g := &Gradient{Stops: append([]GradientStop(nil), stops...), Interpolation: mode}
+	sort.SliceStable(g.Stops, func(i, j int) bool { return g.Stops[i].Pos < g.Stops[j].Pos })
+	return g
+}
+
+// NewCyclicGradient creates a repeating gradient with the colors spread evenly
+// over one period, so that the last color blends back into the first.
+func NewCyclicGradient(mode Interpolation, colors ...ColorRGB) *Gradient {
+	g := &Gradient{Interpolation: mode, Cyclic: true}
+	for i, c := range colors {
+		g.Stops = append(g.Stops, GradientStop{Pos: float64(i) / float64(len(colors)), Color: c})
+	}
+	return g
+}
+
+// At returns the color at t. Outside [0,1] a cyclic gradient repeats and any
+// other gradient keeps the color of its nearest end.
+func (g *Gradient) At(t float64) ColorRGB {
+	n := len(g.Stops)
+	if n == 0 {
+		return ColorRGB{}
+	}
+	if n == 1 {
+		return g.Stops[0].Color
+	}
+
+	if g.Cyclic {
+		t -= math.Floor(t)
+	} else {
+		t = math.Max(0, math.Min(1, t))
+	}
+
+	// a and b are the stops around t; for cyclic gradients the segment
+	// across the end of the period uses the first stop shifted by one.
+	i := sort.Search(n, func(i int) bool { return g.Stops[i].Pos > t })
+	var a, b GradientStop
+	switch {
+	case i == 0 && g.Cyclic:
+		a, b = g.Stops[n-1], g.Stops[0]
+		a.Pos--
+	case i == 0:
+		return g.Stops[0].Color
+	case i == n && g.Cyclic:
+		a, b = g.Stops[n-1], g.Stops[0]
+		b.Pos++
+	case i == n:
+		return g.Stops[n-1].Color
+	default:
+		a, b = g.Stops[i-1], g.Stops[i]
+	}
+
+	if b.Pos <= a.Pos {
+		return b.Color
+	}
+	return g.blend(a.Color, b.Color, (t-a.Pos)/(b.Pos-a.Pos))
+}
+
+// Colors samples n evenly spaced colors, for example to build a palette
+// indexed by iteration count.
+func (g *Gradient) Colors(n int) []ColorRGB {
+	colors := make([]ColorRGB, n)
+	for i := range colors {
+		t := 0.0
+		if g.Cyclic {
+			t = float64(i) / float64(n)
+		} else if n > 1 {
+			t = float64(i) / float64(n-1)
+		}
+		colors[i] = g.At(t)
+	}
+	return colors
+}
+
+// blend mixes a and b by f in the gradient's color space.
+func (g *Gradient) blend(a, b ColorRGB, f float64) ColorRGB {
+	lerp := func(x, y float64) float64 { return x + (y-x)*f }
+
+	switch g.Interpolation {
+	case InterpolateHSL:
+		ha, hb := RGBtoHSL(a), RGBtoHSL(b)
+		// Gray has no hue; borrow the other color's so only S and L change.
+		if ha.S == 0 {
+			ha.H = hb.H
+		}
+		if hb.S == 0 {
+			hb.H = ha.H
+		}
+		dh := hb.H - ha.H
+		if dh > 0.5 {
+			dh--
+		} else if dh < -0.5 {
+			dh++
+		}
+		h := ha.H + dh*f
+		h -= math.Floor(h)
+		return HSLtoRGB(ColorHSL{H: h, S: lerp(ha.S, hb.S), L: lerp(ha.L, hb.L)})
+	case InterpolateOKLab:
+		la, lb := RGBtoOKLab(a), RGBtoOKLab(b)
+		return OKLabtoRGB(ColorOKLab{L: lerp(la.L, lb.L), A: lerp(la.A, lb.A), B: lerp(la.B, lb.B)})
+	default:
+		return lerpRGB(a, b, f)
+	}
+}
+
+// CosinePalette is a cyclic palette defined by
+// color(t) = A + B * cos(2π * (C*t + D)) per channel, as popularized by
+// Inigo Quilez. All vectors hold red, green and blue in X, Y and Z.
+type CosinePalette struct {
+	A, B, C, D vecmath.Vec3
+}
+
+// At returns the color at t. With integer C the palette repeats every unit of t.
+func (p CosinePalette) At(t float64) ColorRGB {
+	channel := func(a, b, c, d float64) uint8 {
+		return clampUint8((a + b*math.Cos(2*math.Pi*(c*t+d))) * 255)
+	}
+	return ColorRGB{
+		R: channel(p.A.X, p.B.X, p.C.X, p.D.X),
+		G: channel(p.A.Y, p.B.Y, p.C.Y, p.D.Y),
+		B: channel(p.A.Z, p.B.Z, p.C.Z, p.D.Z),
+	}
+}
+
+// RainbowPalette is a cosine palette running once around the hue wheel.
+var RainbowPalette = CosinePalette{
+	A: vecmath.Vec3{X: 0.5, Y: 0.5, Z: 0.5},
+	B: vecmath.Vec3{X: 0.5, Y: 0.5, Z: 0.5},
+	C: vecmath.Vec3{X: 1, Y: 1, Z: 1},
+	D: vecmath.Vec3{X: 0, Y: 0.33, Z: 0.67},
+}
+
+// hexColors parses the 0xRRGGBB values of a colormap table.
+func hexColors(values ...uint32) []ColorRGB {
+	colors := make([]ColorRGB, len(values))
+	for i, v := range values {
+		colors[i] = ColorRGB{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v)}
+	}
+	return colors
+}
+
+// The matplotlib colormaps below are sampled at 11 evenly spaced points and
+// blended linearly in between, which stays within a few levels of the originals.
+
+// Viridis returns matplotlib's perceptually uniform blue-green-yellow colormap.
+func Viridis() *Gradient {
+	return NewGradient(InterpolateRGB, hexColors(
+		0x440154, 0x482576, 0x414487, 0x35608d, 0x2a788e, 0x21908c,
+		0x22a884, 0x43bf71, 0x7ad151, 0xbbdf27, 0xfde725)...)
+}
+
+// Magma returns matplotlib's black-purple-orange-white colormap.
+func Magma() *Gradient {
+	return NewGradient(InterpolateRGB, hexColors(
+		0x000004, 0x140e36, 0x3b0f70, 0x641a80, 0x8c2981, 0xb73779,
+		0xde4968, 0xf7705c, 0xfe9f6d, 0xfecf92, 0xfcfdbf)...)
+}
+
+// Inferno returns matplotlib's black-red-yellow colormap.
+func Inferno() *Gradient {
+	return NewGradient(InterpolateRGB, hexColors(
+		0x000004, 0x160b39, 0x420a68, 0x6a176e, 0x932667, 0xbc3754,
+		0xdd513a, 0xf37819, 0xfca50a, 0xf6d746, 0xfcffa4)...)
+}
+
+// Plasma returns matplotlib's blue-magenta-yellow colormap.
+func Plasma() *Gradient {
+	return NewGradient(InterpolateRGB, hexColors(
+		0x0d0887, 0x41049d, 0x6a00a8, 0x8f0da4, 0xb12a90, 0xcc4678,
+		0xe16462, 0xf1834b, 0xfca636, 0xfcce25, 0xf0f921)...)
+}
+
+// Turbo returns Google's improved rainbow colormap, sampled at 33 points
+// from its published polynomial approximation.
+func Turbo() *Gradient {
+	poly := func(t float64, c [6]float64) float64 {
+		return c[0] + t*(c[1]+t*(c[2]+t*(c[3]+t*(c[4]+t*c[5]))))
+	}
+	red := [6]float64{0.13572138, 4.61539260, -42.66032258, 132.13108234, -152.94239396, 59.28637943}
+	green := [6]float64{0.09140261, 2.19418839, 4.84296658, -14.18503333, 4.27729857, 2.82956604}
+	blue := [6]float64{0.10667330, 12.64194608, -60.58204836, 110.36276771, -89.90310912, 27.34824973}
+
+	colors := make([]ColorRGB, 33)
+	for i := range colors {
+		t := float64(i) / float64(len(colors)-1)
+		colors[i] = ColorRGB{
+			R: clampUint8(poly(t, red) * 255),
+			G: clampUint8(poly(t, green) * 255),
+			B: clampUint8(poly(t, blue) * 255),
+		}
+	}
+	return NewGradient(InterpolateRGB, colors...)
+}
+
+// Grayscale returns a black-to-white colormap.
+func Grayscale() *Gradient {
+	return NewGradient(InterpolateRGB, ColorRGB{}, ColorRGB{R: 255, G: 255, B: 255})
+}
+
+// GradientShape selects how FillGradient maps pixels to colormap positions.
+type GradientShape int
+
+const (
+	// GradientHorizontal runs from the left edge (t = 0) to the right edge (t = 1).
+	GradientHorizontal GradientShape = iota
+	// GradientVertical runs from the top edge to the bottom edge.
+	GradientVertical
+	// GradientRadial runs from the center to the corners.
+	GradientRadial
+)
+
+// gradientT returns the colormap position of pixel (x, y) of a w x h rectangle.
+func gradientT(shape GradientShape, x, y, w, h int) float64 {
+	switch shape {
+	case GradientVertical:
+		if h <= 1 {
+			return 0
+		}
+		return float64(y) / float64(h-1)
+	case GradientRadial:
+		dx, dy := float64(x)+0.5-float64(w)/2, float64(y)+0.5-float64(h)/2
+		return math.Hypot(dx, dy) / math.Hypot(float64(w)/2, float64(h)/2)
+	default:
+		if w <= 1 {
+			return 0
+		}
+		return float64(x) / float64(w-1)
+	}
+}
+
+// FillGradient fills the part of the image covered by r with cmap.
+func (img *Image) FillGradient(r Rect, cmap Colormap, shape GradientShape) {
+	area := r.Intersect(img.bounds())
+	for y := area.Y; y < area.Y+area.H; y++ {
+		for x := area.X; x < area.X+area.W; x++ {
+			img.Pixels[y*img.W+x] = cmap.At(gradientT(shape, x-r.X, y-r.Y, r.W, r.H))
+		}
+	}
+}
+
+// DrawGradient writes a gradient filling [x1, x2) x [y1, y2) into the screen
+// buffer. Call DrawBuffer to show it.
+func (screen *Screen) DrawGradient(x1, y1, x2, y2 int, cmap Colormap, shape GradientShape) {
+	r := RectFromCorners(x1, y1, x2, y2)
+	for y := range r.H {
+		for x := range r.W {
+			screen.WritePixel(r.X+x, r.Y+y, cmap.At(gradientT(shape, x, y, r.W, r.H)))
+		}
+	}
+}
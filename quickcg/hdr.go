@@ -0,0 +1,87 @@
+package quickcg
+
+// HDRImage is an image with unbounded linear-light channels, for rendering
+// and accumulating light brighter than the display can show. ToneMap
+// compresses it into an ordinary Image.
+type HDRImage struct {
+	Pixels []ColorLinearRGB
+	W, H   int
+}
+
+// NewHDRImage creates a black HDR image of the given size.
+func NewHDRImage(width, height int) *HDRImage {
+	return &HDRImage{Pixels: make([]ColorLinearRGB, width*height), W: width, H: height}
+}
+
+// At returns the color of the pixel at (x, y).
+// Coordinates outside the image return black.
+func (hdr *HDRImage) At(x, y int) ColorLinearRGB {
+	if x < 0 || y < 0 || x >= hdr.W || y >= hdr.H {
+		return ColorLinearRGB{}
+	}
+	return hdr.Pixels[y*hdr.W+x]
+}
+
+// Set changes the color of the pixel at (x, y).
+// Coordinates outside the image are silently ignored.
+func (hdr *HDRImage) Set(x, y int, c ColorLinearRGB) {
+	if x < 0 || y < 0 || x >= hdr.W || y >= hdr.H {
+		return
+	}
+	hdr.Pixels[y*hdr.W+x] = c
+}
+
+// Add adds light to the pixel at (x, y), for accumulating samples.
+// Coordinates outside the image are silently ignored.
+func (hdr *HDRImage) Add(x, y int, c ColorLinearRGB) {
+	if x < 0 || y < 0 || x >= hdr.W || y >= hdr.H {
+		return
+	}
+	p := &hdr.Pixels[y*hdr.W+x]
+	p.R += c.R
+	p.G += c.G
+	p.B += c.B
+}
+
+// ToneMapOperator selects the curve ToneMap compresses light values with.
+type ToneMapOperator int
+
+const (
+	// ToneMapClamp cuts off everything brighter than 1.
+	ToneMapClamp ToneMapOperator = iota
+	// ToneMapReinhard maps every channel c to c / (1 + c), which never
+	// saturates but flattens bright areas.
+	ToneMapReinhard
+	// ToneMapACES follows Krzysztof Narkowicz's fit of the ACES filmic curve,
+	// with a gentle toe and shoulder and more saturated highlights.
+	ToneMapACES
+)
+
+// apply maps a linear channel value to [0,1].
+func (op ToneMapOperator) apply(c float64) float64 {
+	c = max(c, 0)
+	switch op {
+	case ToneMapReinhard:
+		return c / (1 + c)
+	case ToneMapACES:
+		return (c * (2.51*c + 0.03)) / (c*(2.43*c+0.59) + 0.14)
+	}
+	return c
+}
+
+// ToneMap converts the HDR image to a displayable one: every pixel is
+// multiplied by exposure, compressed by op and encoded as sRGB. The result
+// is written into dst, which may be the screen's BufferImage; only the part
+// where the two images overlap is written.
+func (hdr *HDRImage) ToneMap(dst *Image, op ToneMapOperator, exposure float64) {
+	for y := range min(hdr.H, dst.H) {
+		for x := range min(hdr.W, dst.W) {
+			c := hdr.Pixels[y*hdr.W+x]
+			dst.Pixels[y*dst.W+x] = LinearRGBtoRGB(ColorLinearRGB{
+				R: op.apply(c.R * exposure),
+				G: op.apply(c.G * exposure),
+				B: op.apply(c.B * exposure),
+			})
+		}
+	}
+}
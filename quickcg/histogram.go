@@ -0,0 +1,294 @@
+package quickcg
+
+import (
+	"math"
+)
+
+// Histogram counts how many pixels have each value, 0-255, of the red,
+// green and blue channels and of the luma.
+type Histogram struct {
+	R, G, B, Luma [256]int
+	Total         int // number of pixels counted
+}
+
+// luma returns the Rec. 709 weighted sum of the sRGB channels, the brightness
+// value that image editors show in their histograms.
+func luma(c ColorRGB) int {
+	return (2126*int(c.R) + 7152*int(c.G) + 722*int(c.B) + 5000) / 10000
+}
+
+// shiftLuma moves c by the same amount on every channel so that its luma
+// becomes y, keeping the color differences (and so the hue) unchanged.
+func shiftLuma(c ColorRGB, y float64) ColorRGB {
+	d := y - float64(luma(c))
+	return ColorRGB{
+		R: clampUint8(float64(c.R) + d),
+		G: clampUint8(float64(c.G) + d),
+		B: clampUint8(float64(c.B) + d),
+	}
+}
+
+// Histogram counts the channel and luma values of all pixels of the image.
+func (img *Image) Histogram() Histogram {
+	var h Histogram
+	for _, c := range img.Pixels {
+		h.R[c.R]++
+		h.G[c.G]++
+		h.B[c.B]++
+		h.Luma[luma(c)]++
+	}
+	h.Total = len(img.Pixels)
+	return h
+}
+
+// Otsu returns the luma threshold that best splits the pixels into a dark
+// and a bright class, by maximizing the variance between the two classes.
+// Pass it to Threshold to binarize the image.
+func (h Histogram) Otsu() uint8 {
+	sum := 0.0
+	for v, n := range h.Luma {
+		sum += float64(v * n)
+	}
+
+	best, bestVar := 0, -1.0
+	dark, darkSum := 0, 0.0
+	for t := range 255 {
+		dark += h.Luma[t]
+		darkSum += float64(t * h.Luma[t])
+		bright := h.Total - dark
+		if dark == 0 || bright == 0 {
+			continue
+		}
+		md := darkSum / float64(dark)
+		mb := (sum - darkSum) / float64(bright)
+		v := float64(dark) * float64(bright) * (md - mb) * (md - mb)
+		if v > bestVar {
+			best, bestVar = t+1, v
+		}
+	}
+	return uint8(best)
+}
+
+// Threshold turns pixels with a luma of at least level white and all others black.
+func (img *Image) Threshold(level uint8) {
+	for i, c := range img.Pixels {
+		if luma(c) >= int(level) {
+			img.Pixels[i] = ColorRGB{R: 255, G: 255, B: 255}
+		} else {
+			img.Pixels[i] = ColorRGB{}
+		}
+	}
+}
+
+// Equalize spreads the luma of the image evenly over the full range by
+// mapping it through its cumulative histogram, which brings out detail in
+// low-contrast images. Every channel of a pixel moves by the same amount,
+// so colors keep their hue.
+func (img *Image) Equalize() {
+	h := img.Histogram()
+	lut := equalizeLUT(h.Luma, h.Total)
+	for i, c := range img.Pixels {
+		img.Pixels[i] = shiftLuma(c, lut[luma(c)])
+	}
+}
+
+// equalizeLUT maps each value to its position in the cumulative histogram,
+// scaled so that the darkest value present maps to 0 and the brightest to 255.
+func equalizeLUT(hist [256]int, total int) [256]float64 {
+	var lut [256]float64
+	first := 0
+	for first < 255 && hist[first] == 0 {
+		first++
+	}
+	if total <= hist[first] {
+		for v := range lut {
+			lut[v] = float64(v)
+		}
+		return lut
+	}
+
+	cdf := 0
+	for v, n := range hist {
+		cdf += n
+		lut[v] = math.Max(0, float64(cdf-hist[first])*255/float64(total-hist[first]))
+	}
+	return lut
+}
+
+// CLAHE applies contrast-limited adaptive histogram equalization: the image
+// is split into tiles x tiles regions, each equalized on its own with its
+// histogram clipped at clipLimit times the average bin count, and the results
+// blended smoothly between region centers. It brings out local detail without
+// the noise boost of Equalize; a clip limit of 2-4 is typical.
+func (img *Image) CLAHE(tiles int, clipLimit float64) {
+	nx, ny := min(max(tiles, 1), img.W), min(max(tiles, 1), img.H)
+	if nx == 0 || ny == 0 {
+		return
+	}
+
+	luts := make([][256]float64, nx*ny)
+	for ty := range ny {
+		y0, y1 := ty*img.H/ny, (ty+1)*img.H/ny
+		for tx := range nx {
+			x0, x1 := tx*img.W/nx, (tx+1)*img.W/nx
+
+			var hist [256]int
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					hist[luma(img.Pixels[y*img.W+x])]++
+				}
+			}
+			total := (x1 - x0) * (y1 - y0)
+			clipHistogram(&hist, max(1, int(clipLimit*float64(total)/256)))
+			luts[ty*nx+tx] = clippedLUT(hist, total)
+		}
+	}
+
+	// Blend the four nearest tile mappings bilinearly by the distance to
+	// their centers.
+	tw, th := float64(img.W)/float64(nx), float64(img.H)/float64(ny)
+	for y := range img.H {
+		gy := math.Max(0, math.Min(float64(ny-1), (float64(y)+0.5)/th-0.5))
+		ty0 := int(gy)
+		ty1, fy := min(ty0+1, ny-1), gy-float64(ty0)
+		for x := range img.W {
+			gx := math.Max(0, math.Min(float64(nx-1), (float64(x)+0.5)/tw-0.5))
+			tx0 := int(gx)
+			tx1, fx := min(tx0+1, nx-1), gx-float64(tx0)
+
+			c := img.Pixels[y*img.W+x]
+			v := luma(c)
+			top := luts[ty0*nx+tx0][v]*(1-fx) + luts[ty0*nx+tx1][v]*fx
+			bottom := luts[ty1*nx+tx0][v]*(1-fx) + luts[ty1*nx+tx1][v]*fx
+			img.Pixels[y*img.W+x] = shiftLuma(c, top*(1-fy)+bottom*fy)
+		}
+	}
+}
+
+// clipHistogram caps every bin at limit and spreads the clipped counts
+// evenly over all bins.
+func clipHistogram(hist *[256]int, limit int) {
+	excess := 0
+	for v, n := range hist {
+		if n > limit {
+			excess += n - limit
+			hist[v] = limit
+		}
+	}
+	for v := range hist {
+		hist[v] += excess / 256
+	}
+	// Spread the remainder evenly over the range rather than piling it into
+	// the darkest bins.
+	if rem := excess % 256; rem > 0 {
+		step := max(256/rem, 1)
+		for v := 0; v < 256 && rem > 0; v += step {
+			hist[v]++
+			rem--
+		}
+	}
+}
+
+// clippedLUT maps each value to its position in the cumulative histogram.
+// Unlike equalizeLUT it does not stretch the darkest value to 0, since
+// clipping leaves every bin populated.
+func clippedLUT(hist [256]int, total int) [256]float64 {
+	var lut [256]float64
+	cdf := 0
+	for v, n := range hist {
+		cdf += n
+		lut[v] = float64(cdf) * 255 / float64(max(total, 1))
+	}
+	return lut
+}
+
+// AutoLevels stretches each channel so that its darkest values become 0 and
+// its brightest 255, ignoring the fraction clip (for example 0.005) of pixels
+// at either end so that a few outliers do not stop the stretch. Stretching
+// the channels separately also removes a uniform color cast.
+func (img *Image) AutoLevels(clip float64) {
+	h := img.Histogram()
+	skip := int(clip * float64(h.Total))
+
+	levels := func(hist [256]int) [256]uint8 {
+		lo, hi := 0, 255
+		for acc := 0; lo < 255; lo++ {
+			if acc += hist[lo]; acc > skip {
+				break
+			}
+		}
+		for acc := 0; hi > 0; hi-- {
+			if acc += hist[hi]; acc > skip {
+				break
+			}
+		}
+
+		var lut [256]uint8
+		for v := range lut {
+			if hi <= lo {
+				lut[v] = uint8(v)
+			} else {
+				lut[v] = clampUint8(float64(v-lo) * 255 / float64(hi-lo))
+			}
+		}
+		return lut
+	}
+
+	r, g, b := levels(h.R), levels(h.G), levels(h.B)
+	for i, c := range img.Pixels {
+		img.Pixels[i] = ColorRGB{R: r[c.R], G: g[c.G], B: b[c.B]}
+	}
+}
+
+// DrawHistogram writes a plot of hist covering [x1, x2) x [y1, y2) into the
+// screen buffer: the red, green and blue counts as overlapping additive bars
+// and the luma as a white outline. Call DrawBuffer to show it.
+func (screen *Screen) DrawHistogram(x1, y1, x2, y2 int, hist Histogram) {
+	r := RectFromCorners(x1, y1, x2, y2)
+	if r.Empty() {
+		return
+	}
+
+	peak := 1
+	for v := range 256 {
+		peak = max(peak, hist.R[v], hist.G[v], hist.B[v], hist.Luma[v])
+	}
+
+	// height returns the bar height of column x, taking the largest bin the
+	// column covers when the plot is narrower than 256 pixels.
+	height := func(bins *[256]int, x int) int {
+		lo, hi := x*256/r.W, max((x+1)*256/r.W, x*256/r.W+1)
+		n := 0
+		for v := lo; v < min(hi, 256); v++ {
+			n = max(n, bins[v])
+		}
+		return (n*r.H + peak - 1) / peak
+	}
+
+	prev := -1
+	for x := range r.W {
+		hr, hg, hb := height(&hist.R, x), height(&hist.G, x), height(&hist.B, x)
+		hl := height(&hist.Luma, x)
+		if prev < 0 {
+			prev = hl
+		}
+		lo, hi := min(prev, hl), max(prev, hl)
+		for row := range r.H {
+			c := ColorRGB{R: 24, G: 24, B: 24}
+			if row < hr {
+				c.R = 200
+			}
+			if row < hg {
+				c.G = 200
+			}
+			if row < hb {
+				c.B = 200
+			}
+			if row >= max(lo-1, 0) && row <= hi-1 {
+				c = ColorRGB{R: 255, G: 255, B: 255}
+			}
+			screen.WritePixel(r.X+x, r.Y+r.H-1-row, c)
+		}
+		prev = hl
+	}
+}
@@ -0,0 +1,125 @@
+package quickcg
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+)
+
+// NewImage creates a black image of the given size.
+func NewImage(width, height int) *Image {
+	return &Image{
+		Pixels: make([]ColorRGB, width*height),
+		W:      width,
+		H:      height,
+	}
+}
+
+// NewImageFromPixels wraps a row-major pixel slice, such as the one returned
+// by LoadPNG, into an Image. The slice is not copied.
+func NewImageFromPixels(pixels []ColorRGB, width, height int) *Image {
+	return &Image{Pixels: pixels, W: width, H: height}
+}
+
+// LoadImage loads a PNG file into an Image.
+func LoadImage(path string) (*Image, error) {
+	pixels, w, h, err := LoadPNG(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewImageFromPixels(pixels, w, h), nil
+}
+
+// At returns the color of the pixel at (x, y).
+// Coordinates outside the image return black.
+func (img *Image) At(x, y int) ColorRGB {
+	if x < 0 || y < 0 || x >= img.W || y >= img.H {
+		return ColorRGB{}
+	}
+	return img.Pixels[y*img.W+x]
+}
+
+// Set changes the color of the pixel at (x, y).
+// Coordinates outside the image are silently ignored.
+func (img *Image) Set(x, y int, color ColorRGB) {
+	if x < 0 || y < 0 || x >= img.W || y >= img.H {
+		return
+	}
+	img.Pixels[y*img.W+x] = color
+}
+
+// Clone returns a deep copy of the image.
+func (img *Image) Clone() *Image {
+	return &Image{
+		Pixels: append([]ColorRGB(nil), img.Pixels...),
+		W:      img.W,
+		H:      img.H,
+	}
+}
+
+// ToRGBA converts the image to a standard library *image.RGBA.
+func (img *Image) ToRGBA() *image.RGBA {
+	rgba := image.NewRGBA(image.Rect(0, 0, img.W, img.H))
+	for i, c := range img.Pixels {
+		rgba.Pix[i*4+0] = c.R
+		rgba.Pix[i*4+1] = c.G
+		rgba.Pix[i*4+2] = c.B
+		rgba.Pix[i*4+3] = 255
+	}
+	return rgba
+}
+
+// SavePNG writes the image to a PNG file at the given path.
+func (img *Image) SavePNG(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	err = png.Encode(file, img.ToRGBA())
+	if err != nil {
+		return fmt.Errorf("failed to encode PNG: %w", err)
+	}
+
+	return nil
+}
+
+// BufferImage returns an Image that shares its pixels with the screen buffer,
+// so image operations on it write directly into what DrawBuffer shows.
+func (screen *Screen) BufferImage() *Image {
+	return NewImageFromPixels(screen.buffer, screen.w, screen.h)
+}
+
+// WriteImage writes img into the screen buffer with its top-left corner at
+// (x, y). Like WritePixel, it applies the drawing origin and skips pixels
+// outside the clip rectangle. Call DrawBuffer to show the result.
+func (screen *Screen) WriteImage(img *Image, x, y int) {
+	x += screen.ox
+	y += screen.oy
+	r, ok := ClipRect(Rect{X: x, Y: y, W: img.W, H: img.H}, screen.clip)
+	if !ok {
+		return
+	}
+
+	for py := r.Y; py < r.Y+r.H; py++ {
+		src := img.Pixels[(py-y)*img.W+r.X-x:]
+		copy(screen.buffer[py*screen.w+r.X:py*screen.w+r.X+r.W], src[:r.W])
+	}
+}
+
+// Crop returns a copy of the part of the image covered by r.
+// The rectangle is clipped to the image bounds.
+func (img *Image) Crop(r Rect) *Image {
+	x0, y0 := max(r.X, 0), max(r.Y, 0)
+	x1, y1 := min(r.X+r.W, img.W), min(r.Y+r.H, img.H)
+	out := NewImage(max(x1-x0, 0), max(y1-y0, 0))
+
+	for y := range out.H {
+		copy(out.Pixels[y*out.W:(y+1)*out.W], img.Pixels[(y0+y)*img.W+x0:])
+	}
+
+	return out
+}
@@ -48,7 +48,13 @@ func LoadPNG(path string) ([]ColorRGB, int, int, error) {
 }
 
 // SaveScreenAsPNG saves the current contents of the screen to a PNG file at the given path.
+// It reads the pixels back from the renderer, which is slow and depends on the
+// rendering backend; use SaveCapture for deterministic screenshots.
 func (scr *Screen) SaveScreenAsPNG(path string) error {
+	if scr.headless() {
+		return fmt.Errorf("headless screens have no renderer to read from, use SaveCapture")
+	}
+
 	img := image.NewRGBA(image.Rect(0, 0, scr.w, scr.h))
 
 	pixelData := make([]byte, scr.w*scr.h*4)
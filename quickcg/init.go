@@ -56,6 +56,8 @@ func NewScreen(width, height int, fullscreen bool, title string) (*Screen, error
 	}
 
 	scr.buffer = make([]ColorRGB, scr.w * scr.h)
+	scr.allocFrames()
+	scr.clip = scr.bounds()
 
 	scr.surface, err = scr.window.GetSurface()
 	if err != nil {
@@ -66,6 +68,23 @@ func NewScreen(width, height int, fullscreen bool, title string) (*Screen, error
 	return &scr, nil
 }
 
+// NewHeadlessScreen creates a screen without a window, for offline rendering.
+// It does not initialize SDL: all drawing goes to the CPU copy of the frame,
+// which can be read with Capture or streamed to a FrameSink.
+func NewHeadlessScreen(width, height int) *Screen {
+	scr := Screen{w: width, h: height}
+	scr.buffer = make([]ColorRGB, scr.w * scr.h)
+	scr.allocFrames()
+	scr.clip = scr.bounds()
+
+	return &scr
+}
+
+// headless reports whether the screen was created without a window.
+func (screen *Screen) headless() bool {
+	return screen.window == nil
+}
+
 // GetWidth returns the width of the screen in pixels.
 func (screen *Screen) GetWidth() int {
 	return screen.w
@@ -100,6 +119,10 @@ func Done(delay uint32) bool {
 // Close safely destroys the window and renderer resources associated with the screen.
 // Should be called when the screen is no longer needed.
 func (screen *Screen) Close() error {
+	if screen.headless() {
+		return nil
+	}
+
 	var err error
 
 	err = screen.renderer.Destroy()
@@ -121,6 +144,10 @@ func (screen *Screen) Close() error {
 // or a global SDL_QuitEvent is received.
 // It polls events with a small delay to avoid high CPU usage.
 func (screen *Screen) Sleep() error {
+	if screen.headless() {
+		return nil
+	}
+
     for {
         for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
             switch e := event.(type) {
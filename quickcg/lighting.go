@@ -0,0 +1,128 @@
+package quickcg
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// ShadingMode selects where Renderer3D evaluates lighting.
+type ShadingMode int
+
+const (
+	// ShadeUnlit draws texture or vertex colors without lighting.
+	ShadeUnlit ShadingMode = iota
+	// ShadeFlat lights each triangle once, using its face normal.
+	ShadeFlat
+	// ShadeGouraud lights each vertex and interpolates the result.
+	ShadeGouraud
+	// ShadePhong interpolates normals and lights every pixel.
+	ShadePhong
+)
+
+// LightKind is the type of a Light.
+type LightKind int
+
+const (
+	// LightAmbient lights every surface equally from all directions.
+	LightAmbient LightKind = iota
+	// LightDirectional shines in one direction from infinitely far away, like the sun.
+	LightDirectional
+	// LightPoint shines in all directions from a position.
+	LightPoint
+)
+
+// Light is a light source used by the shading modes of Renderer3D.
+type Light struct {
+	Kind      LightKind
+	Color     ColorRGB
+	Intensity float64
+	Direction vecmath.Vec3 // direction the light travels (directional lights)
+	Position  vecmath.Vec3 // world position (point lights)
+	// Attenuation dims point lights with distance d by 1 / (1 + Attenuation*d*d).
+	Attenuation float64
+}
+
+// AmbientLight returns an ambient light.
+func AmbientLight(color ColorRGB, intensity float64) Light {
+	return Light{Kind: LightAmbient, Color: color, Intensity: intensity}
+}
+
+// DirectionalLight returns a light shining in direction.
+func DirectionalLight(direction vecmath.Vec3, color ColorRGB, intensity float64) Light {
+	return Light{Kind: LightDirectional, Direction: direction.Normalize(), Color: color, Intensity: intensity}
+}
+
+// PointLight returns a light at position, without attenuation.
+func PointLight(position vecmath.Vec3, color ColorRGB, intensity float64) Light {
+	return Light{Kind: LightPoint, Position: position, Color: color, Intensity: intensity}
+}
+
+// Fragment holds the interpolated attributes of a pixel covered by a triangle.
+// It is passed to the FragmentShader of a Renderer3D.
+type Fragment struct {
+	X, Y     int          // screen pixel coordinates
+	Depth    float64      // depth buffer value, 0 at the near plane and 1 at the far plane
+	Position vecmath.Vec3 // world position
+	Normal   vecmath.Vec3 // unit world-space normal
+	UV       vecmath.Vec2 // texture coordinates
+	Color    ColorRGB     // texture sample, or the vertex color for untextured triangles
+	Texture  *Image       // texture of the triangle, nil if none
+}
+
+// FragmentShader computes the color of a pixel. Set it on a Renderer3D
+// to replace the built-in shading.
+type FragmentShader func(frag Fragment) ColorRGB
+
+// lighting returns the diffuse and specular light reaching a surface at pos
+// with unit normal n. The diffuse part includes ambient light and scales the
+// surface color; the specular part is added on top of it.
+func (r *Renderer3D) lighting(pos, n vecmath.Vec3) (vecmath.Vec3, vecmath.Vec3) {
+	var diffuse, specular vecmath.Vec3
+	view := r.Camera.Position.Sub(pos).Normalize()
+
+	for _, light := range r.Lights {
+		color := colorVec(light.Color).Scale(light.Intensity)
+
+		var l vecmath.Vec3
+		switch light.Kind {
+		case LightAmbient:
+			diffuse = diffuse.Add(color)
+			continue
+		case LightDirectional:
+			l = light.Direction.Normalize().Neg()
+		case LightPoint:
+			d := light.Position.Sub(pos)
+			l = d.Normalize()
+			color = color.Scale(1 / (1 + light.Attenuation*d.LenSq()))
+		}
+
+		ndotl := n.Dot(l)
+		if ndotl <= 0 {
+			continue
+		}
+		diffuse = diffuse.Add(color.Scale(ndotl))
+
+		if r.Specular <= 0 {
+			continue
+		}
+		var s float64
+		if r.BlinnPhong {
+			s = n.Dot(l.Add(view).Normalize())
+		} else {
+			s = l.Neg().Reflect(n).Dot(view)
+		}
+		if s > 0 {
+			specular = specular.Add(color.Scale(r.Specular * math.Pow(s, r.Shininess)))
+		}
+	}
+
+	return diffuse, specular
+}
+
+// Illuminate applies the renderer's lights to frag.Color at the fragment's
+// position and normal. Fragment shaders can use it as a starting point.
+func (r *Renderer3D) Illuminate(frag Fragment) ColorRGB {
+	diffuse, specular := r.lighting(frag.Position, frag.Normal)
+	return vecColor(colorVec(frag.Color).Mul(diffuse).Add(specular))
+}
@@ -0,0 +1,297 @@
+package quickcg
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// Material describes the surface of a mesh group, as loaded from an MTL file.
+type Material struct {
+	Name    string
+	Diffuse ColorRGB // Kd
+	Texture *Image   // map_Kd, nil if the material has no texture
+}
+
+// MeshIndex refers to the attributes of one triangle corner.
+// UV and Normal are -1 when the face does not specify them.
+type MeshIndex struct {
+	Pos, UV, Normal int
+}
+
+// MeshTriangle is a triangle of a mesh.
+type MeshTriangle [3]MeshIndex
+
+// MeshGroup is a run of triangles sharing a group name and a material.
+type MeshGroup struct {
+	Name      string
+	Material  *Material
+	Triangles []MeshTriangle
+}
+
+// Mesh is a triangle mesh with shared vertex attributes.
+type Mesh struct {
+	Positions []vecmath.Vec3
+	UVs       []vecmath.Vec2
+	Normals   []vecmath.Vec3
+	Groups    []MeshGroup
+	Materials map[string]*Material
+}
+
+// defaultMaterial is used for faces without a usemtl statement or with an
+// unknown material.
+var defaultMaterial = &Material{Name: "default", Diffuse: ColorRGB{R: 255, G: 255, B: 255}}
+
+// LoadOBJ loads a Wavefront OBJ file. Polygons with more than three corners
+// are split into triangle fans, so they are expected to be convex. Materials
+// referenced with mtllib are loaded from paths relative to the OBJ file.
+// Faces whose material is unknown, or whose MTL file cannot be loaded, get
+// a plain white material so that the geometry is still usable.
+//
+// Texture coordinates are flipped vertically on load, so that (0, 0) is the
+// top-left texel as everywhere else in quickcg.
+func LoadOBJ(path string) (*Mesh, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading file: %s", err)
+	}
+	defer file.Close()
+
+	mesh := &Mesh{Materials: make(map[string]*Material)}
+	groupName := "default"
+	material := defaultMaterial
+	var group *MeshGroup
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch fields[0] {
+		case "v":
+			v, err := parseFloats(fields[1:], 3)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", path, lineNo, err)
+			}
+			mesh.Positions = append(mesh.Positions, vecmath.Vec3{X: v[0], Y: v[1], Z: v[2]})
+		case "vt":
+			// v and w are optional; w is ignored.
+			v, err := parseFloats(fields[1:], max(min(len(fields)-1, 2), 1))
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", path, lineNo, err)
+			}
+			v = append(v, 0)
+			mesh.UVs = append(mesh.UVs, vecmath.Vec2{X: v[0], Y: 1 - v[1]})
+		case "vn":
+			v, err := parseFloats(fields[1:], 3)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", path, lineNo, err)
+			}
+			mesh.Normals = append(mesh.Normals, vecmath.Vec3{X: v[0], Y: v[1], Z: v[2]}.Normalize())
+		case "f":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("%s:%d: face needs at least 3 vertices", path, lineNo)
+			}
+			corners := make([]MeshIndex, len(fields)-1)
+			for i, f := range fields[1:] {
+				corners[i], err = mesh.parseFaceIndex(f)
+				if err != nil {
+					return nil, fmt.Errorf("%s:%d: %s", path, lineNo, err)
+				}
+			}
+			if group == nil {
+				mesh.Groups = append(mesh.Groups, MeshGroup{Name: groupName, Material: material})
+				group = &mesh.Groups[len(mesh.Groups)-1]
+			}
+			for i := 1; i+1 < len(corners); i++ {
+				group.Triangles = append(group.Triangles, MeshTriangle{corners[0], corners[i], corners[i+1]})
+			}
+		case "g", "o":
+			groupName = strings.Join(fields[1:], " ")
+			group = nil
+		case "usemtl":
+			name := strings.Join(fields[1:], " ")
+			material = mesh.Materials[name]
+			if material == nil {
+				material = defaultMaterial
+			}
+			group = nil
+		case "mtllib":
+			for _, lib := range fields[1:] {
+				materials, err := LoadMTL(filepath.Join(filepath.Dir(path), lib))
+				if err != nil {
+					// Like unknown materials, faces of a missing library use defaultMaterial.
+					continue
+				}
+				for name, m := range materials {
+					mesh.Materials[name] = m
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading file: %s", err)
+	}
+
+	return mesh, nil
+}
+
+// parseFaceIndex parses a face corner such as "3", "3/1", "3//2" or "3/1/2".
+// OBJ indices start at 1; negative indices count back from the last vertex.
+func (mesh *Mesh) parseFaceIndex(s string) (MeshIndex, error) {
+	parts := strings.Split(s, "/")
+	idx := MeshIndex{Pos: -1, UV: -1, Normal: -1}
+	counts := []int{len(mesh.Positions), len(mesh.UVs), len(mesh.Normals)}
+	targets := []*int{&idx.Pos, &idx.UV, &idx.Normal}
+
+	for i, part := range parts {
+		if i >= 3 || part == "" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return idx, fmt.Errorf("invalid face index %q", s)
+		}
+		if n < 0 {
+			n += counts[i]
+		} else {
+			n--
+		}
+		if n < 0 || n >= counts[i] {
+			return idx, fmt.Errorf("face index %q out of range", s)
+		}
+		*targets[i] = n
+	}
+
+	if idx.Pos < 0 {
+		return idx, fmt.Errorf("face index %q has no position", s)
+	}
+	return idx, nil
+}
+
+// LoadMTL loads the materials of a Wavefront MTL file, keyed by name.
+// Diffuse textures (map_Kd) are loaded with LoadImage from paths relative
+// to the MTL file.
+func LoadMTL(path string) (map[string]*Material, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading file: %s", err)
+	}
+	defer file.Close()
+
+	materials := make(map[string]*Material)
+	var current *Material
+
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if fields[0] == "newmtl" {
+			name := strings.Join(fields[1:], " ")
+			current = &Material{Name: name, Diffuse: defaultMaterial.Diffuse}
+			materials[name] = current
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch fields[0] {
+		case "Kd":
+			v, err := parseFloats(fields[1:], 3)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %s", path, lineNo, err)
+			}
+			current.Diffuse = vecColor(vecmath.Vec3{X: v[0], Y: v[1], Z: v[2]})
+		case "map_Kd":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%s:%d: map_Kd needs a file name", path, lineNo)
+			}
+			// Options such as -s or -o come first; the file name is last.
+			texture, err := LoadImage(filepath.Join(filepath.Dir(path), fields[len(fields)-1]))
+			if err != nil {
+				return nil, err
+			}
+			current.Texture = texture
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading file: %s", err)
+	}
+
+	return materials, nil
+}
+
+func parseFloats(fields []string, n int) ([]float64, error) {
+	if len(fields) < n {
+		return nil, fmt.Errorf("expected %d numbers, got %d", n, len(fields))
+	}
+
+	values := make([]float64, n)
+	for i := range n {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", fields[i])
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// Bounds returns the corners of the axis-aligned box enclosing all positions.
+func (mesh *Mesh) Bounds() (vecmath.Vec3, vecmath.Vec3) {
+	if len(mesh.Positions) == 0 {
+		return vecmath.Vec3{}, vecmath.Vec3{}
+	}
+
+	lo := vecmath.Vec3{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	hi := vecmath.Vec3{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, p := range mesh.Positions {
+		lo = vecmath.Vec3{X: min(lo.X, p.X), Y: min(lo.Y, p.Y), Z: min(lo.Z, p.Z)}
+		hi = vecmath.Vec3{X: max(hi.X, p.X), Y: max(hi.Y, p.Y), Z: max(hi.Z, p.Z)}
+	}
+	return lo, hi
+}
+
+// vertex returns the renderer vertex for a triangle corner.
+func (mesh *Mesh) vertex(idx MeshIndex, material *Material) Vertex3D {
+	v := Vertex3D{Pos: mesh.Positions[idx.Pos], Color: material.Diffuse}
+	if idx.UV >= 0 {
+		v.UV = mesh.UVs[idx.UV]
+	}
+	if idx.Normal >= 0 {
+		v.Normal = mesh.Normals[idx.Normal]
+	}
+	return v
+}
+
+// Draw renders the mesh with r, using each group's material texture or diffuse color.
+func (mesh *Mesh) Draw(r *Renderer3D) {
+	for _, group := range mesh.Groups {
+		material := group.Material
+		if material == nil {
+			material = defaultMaterial
+		}
+		for _, tri := range group.Triangles {
+			r.DrawTriangle(
+				mesh.vertex(tri[0], material),
+				mesh.vertex(tri[1], material),
+				mesh.vertex(tri[2], material),
+				material.Texture,
+			)
+		}
+	}
+}
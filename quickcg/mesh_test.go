@@ -0,0 +1,125 @@
+package quickcg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// loadTestOBJ writes the OBJ source to a temporary file and loads it.
+func loadTestOBJ(t *testing.T, src string) *Mesh {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "test.obj")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mesh, err := LoadOBJ(path)
+	if err != nil {
+		t.Fatalf("LoadOBJ: %v", err)
+	}
+	return mesh
+}
+
+func TestLoadOBJPolygonFan(t *testing.T) {
+	mesh := loadTestOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 1 1 0
+v 0.5 2 0
+v 0 1 0
+f 1 2 3 4 5
+`)
+	if len(mesh.Groups) != 1 {
+		t.Fatalf("got %d groups, want 1", len(mesh.Groups))
+	}
+	want := [][3]int{{0, 1, 2}, {0, 2, 3}, {0, 3, 4}}
+	tris := mesh.Groups[0].Triangles
+	if len(tris) != len(want) {
+		t.Fatalf("got %d triangles, want %d", len(tris), len(want))
+	}
+	for i, tri := range tris {
+		for k := range 3 {
+			if tri[k].Pos != want[i][k] || tri[k].UV != -1 || tri[k].Normal != -1 {
+				t.Errorf("triangle %d corner %d = %+v, want position %d only", i, k, tri[k], want[i][k])
+			}
+		}
+	}
+}
+
+func TestLoadOBJIndices(t *testing.T) {
+	mesh := loadTestOBJ(t, `
+v 0 0 0
+v 1 0 0
+v 0 1 0
+vt 0.25
+vt 0.5 0.75
+vt 1 1 0
+vn 0 0 2
+f -3//-1 -2//1 -1//1
+f 1/1 2/2 3/3
+f 1/1/1 2/2/1 3/-1/-1
+`)
+	wantUVs := []vecmath.Vec2{{X: 0.25, Y: 1}, {X: 0.5, Y: 0.25}, {X: 1, Y: 0}}
+	if len(mesh.UVs) != len(wantUVs) {
+		t.Fatalf("got %d UVs, want %d", len(mesh.UVs), len(wantUVs))
+	}
+	for i, uv := range mesh.UVs {
+		if uv != wantUVs[i] {
+			t.Errorf("UV %d = %v, want %v", i, uv, wantUVs[i])
+		}
+	}
+	if n := mesh.Normals[0]; n != (vecmath.Vec3{Z: 1}) {
+		t.Errorf("normal = %v, want it normalized to (0, 0, 1)", n)
+	}
+
+	want := []MeshTriangle{
+		{{Pos: 0, UV: -1, Normal: 0}, {Pos: 1, UV: -1, Normal: 0}, {Pos: 2, UV: -1, Normal: 0}},
+		{{Pos: 0, UV: 0, Normal: -1}, {Pos: 1, UV: 1, Normal: -1}, {Pos: 2, UV: 2, Normal: -1}},
+		{{Pos: 0, UV: 0, Normal: 0}, {Pos: 1, UV: 1, Normal: 0}, {Pos: 2, UV: 2, Normal: 0}},
+	}
+	tris := mesh.Groups[0].Triangles
+	if len(tris) != len(want) {
+		t.Fatalf("got %d triangles, want %d", len(tris), len(want))
+	}
+	for i := range want {
+		if tris[i] != want[i] {
+			t.Errorf("triangle %d = %+v, want %+v", i, tris[i], want[i])
+		}
+	}
+}
+
+func TestLoadOBJMissingMaterials(t *testing.T) {
+	mesh := loadTestOBJ(t, `
+mtllib missing.mtl
+v 0 0 0
+v 1 0 0
+v 0 1 0
+usemtl nowhere
+f 1 2 3
+`)
+	if m := mesh.Groups[0].Material; m != defaultMaterial {
+		t.Errorf("material = %+v, want the default material", m)
+	}
+}
+
+func TestLoadOBJErrors(t *testing.T) {
+	for _, src := range []string{
+		"v 0 0\n",
+		"vt\n",
+		"v 0 0 0\nv 1 0 0\nf 1 2\n",
+		"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 4\n",
+		"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 -4\n",
+		"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1/1 2/1 3/1\n",
+		"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 x\n",
+	} {
+		path := filepath.Join(t.TempDir(), "test.obj")
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadOBJ(path); err == nil {
+			t.Errorf("LoadOBJ(%q) succeeded, want an error", src)
+		}
+	}
+}
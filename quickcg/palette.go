@@ -0,0 +1,241 @@
+package quickcg
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Palette is a table of up to 256 colors for indexed rendering.
+// A Gradient's Colors(256) makes a smooth one.
+type Palette []ColorRGB
+
+// Rotate shifts the entries first through last (inclusive) by steps places,
+// moving each entry to a higher index for positive steps. Rotating part of a
+// palette every frame animates an indexed image without touching its pixels.
+func (p Palette) Rotate(first, last, steps int) {
+	first = max(first, 0)
+	last = min(last, len(p)-1)
+	n := last - first + 1
+	if n <= 1 {
+		return
+	}
+
+	steps %= n
+	if steps < 0 {
+		steps += n
+	}
+	span := p[first : last+1]
+	rotated := append(append(Palette(nil), span[n-steps:]...), span[:n-steps]...)
+	copy(span, rotated)
+}
+
+// Fade returns a copy of the palette blended toward color by t in [0,1],
+// for example toward black for a fade-out.
+func (p Palette) Fade(color ColorRGB, t float64) Palette {
+	faded := make(Palette, len(p))
+	for i, c := range p {
+		faded[i] = lerpRGB(c, color, t)
+	}
+	return faded
+}
+
+// FadePalette blends two palettes entry by entry, from a at t = 0 to b at t = 1.
+// Entries missing from the shorter palette count as black.
+func FadePalette(a, b Palette, t float64) Palette {
+	faded := make(Palette, max(len(a), len(b)))
+	for i := range faded {
+		var ca, cb ColorRGB
+		if i < len(a) {
+			ca = a[i]
+		}
+		if i < len(b) {
+			cb = b[i]
+		}
+		faded[i] = lerpRGB(ca, cb, t)
+	}
+	return faded
+}
+
+func lerpRGB(a, b ColorRGB, t float64) ColorRGB {
+	return ColorRGB{
+		R: clampUint8(float64(a.R) + (float64(b.R)-float64(a.R))*t),
+		G: clampUint8(float64(a.G) + (float64(b.G)-float64(a.G))*t),
+		B: clampUint8(float64(a.B) + (float64(b.B)-float64(a.B))*t),
+	}
+}
+
+// LoadPalette loads a GIMP (.gpl), JASC (.pal) or Adobe Color Table (.act)
+// palette, chosen by the file extension.
+func LoadPalette(path string) (Palette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Error loading file: %s", err)
+	}
+
+	var p Palette
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gpl":
+		p, err = parseGPL(data)
+	case ".pal":
+		p, err = parseJASC(data)
+	case ".act":
+		p, err = parseACT(data)
+	default:
+		return nil, fmt.Errorf("unknown palette format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return p, nil
+}
+
+// parseGPL parses a GIMP palette: a "GIMP Palette" header, optional Name and
+// Columns lines and comments, then one "R G B [name]" line per color.
+func parseGPL(data []byte) (Palette, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "GIMP Palette" {
+		return nil, fmt.Errorf("missing GIMP Palette header")
+	}
+
+	var p Palette
+	for lineNo := 2; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "Name:") || strings.HasPrefix(line, "Columns:") {
+			continue
+		}
+		c, err := parseRGBFields(strings.Fields(line))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", lineNo, err)
+		}
+		p = append(p, c)
+	}
+	return p, scanner.Err()
+}
+
+// parseJASC parses a JASC (Paint Shop Pro) palette: "JASC-PAL", a version
+// line, the color count, then one "R G B" line per color.
+func parseJASC(data []byte) (Palette, error) {
+	lines := strings.Fields(strings.ReplaceAll(string(data), "\r", ""))
+	if len(lines) < 3 || lines[0] != "JASC-PAL" {
+		return nil, fmt.Errorf("missing JASC-PAL header")
+	}
+
+	count, err := strconv.Atoi(lines[2])
+	if err != nil || count < 0 || count > 256 {
+		return nil, fmt.Errorf("invalid color count %q", lines[2])
+	}
+	values := lines[3:]
+	if len(values) != count*3 {
+		return nil, fmt.Errorf("expected %d colors, got %d values", count, len(values))
+	}
+
+	p := make(Palette, count)
+	for i := range p {
+		p[i], err = parseRGBFields(values[i*3 : i*3+3])
+		if err != nil {
+			return nil, fmt.Errorf("color %d: %s", i, err)
+		}
+	}
+	return p, nil
+}
+
+// parseACT parses an Adobe Color Table: 256 RGB triplets, optionally followed
+// by a big-endian color count and transparent index.
+func parseACT(data []byte) (Palette, error) {
+	if len(data) != 768 && len(data) != 772 {
+		return nil, fmt.Errorf("unexpected size %d, want 768 or 772 bytes", len(data))
+	}
+
+	count := 256
+	if len(data) == 772 {
+		if n := int(data[768])<<8 | int(data[769]); n > 0 && n <= 256 {
+			count = n
+		}
+	}
+
+	p := make(Palette, count)
+	for i := range p {
+		p[i] = ColorRGB{R: data[i*3], G: data[i*3+1], B: data[i*3+2]}
+	}
+	return p, nil
+}
+
+func parseRGBFields(fields []string) (ColorRGB, error) {
+	if len(fields) < 3 {
+		return ColorRGB{}, fmt.Errorf("expected 3 color values, got %d", len(fields))
+	}
+
+	var v [3]uint8
+	for i := range v {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil || n < 0 || n > 255 {
+			return ColorRGB{}, fmt.Errorf("invalid color value %q", fields[i])
+		}
+		v[i] = uint8(n)
+	}
+	return ColorRGB{R: v[0], G: v[1], B: v[2]}, nil
+}
+
+// SetIndexed switches indexed color mode on or off. In indexed mode the
+// screen keeps a buffer of palette indices, written with WriteIndex, and
+// DrawBuffer resolves it through the palette into the pixel buffer before
+// showing it. Changing the palette recolors the whole image at the next
+// DrawBuffer, which makes color cycling free of per-pixel work in your code.
+// Until SetPalette is called, the palette is a grayscale ramp.
+func (screen *Screen) SetIndexed(enabled bool) {
+	screen.indexed = enabled
+	if enabled && screen.indices == nil {
+		screen.indices = make([]uint8, screen.w*screen.h)
+	}
+	if screen.palette == nil {
+		screen.palette = Grayscale().Colors(256)
+	}
+}
+
+// IsIndexed reports whether the screen is in indexed color mode.
+func (screen *Screen) IsIndexed() bool {
+	return screen.indexed
+}
+
+// SetPalette replaces the palette used in indexed mode. Indices beyond the
+// end of a shorter palette show black. The palette is not copied, so later
+// changes to p, such as Rotate, show up at the next DrawBuffer.
+func (screen *Screen) SetPalette(p Palette) {
+	screen.palette = p
+}
+
+// Palette returns the palette used in indexed mode.
+func (screen *Screen) Palette() Palette {
+	return screen.palette
+}
+
+// WriteIndex writes a palette index to the index buffer at (x, y).
+// Coordinates outside the clip rectangle are silently ignored.
+func (screen *Screen) WriteIndex(x, y int, index uint8) {
+	x += screen.ox
+	y += screen.oy
+	if !screen.clip.Contains(x, y) || screen.indices == nil {
+		return
+	}
+	screen.indices[y*screen.w+x] = index
+}
+
+// IndexBuffer returns the index buffer in row-major order, for writing many
+// indices at once. It is nil until indexed mode is first enabled.
+func (screen *Screen) IndexBuffer() []uint8 {
+	return screen.indices
+}
+
+// resolveIndices fills the pixel buffer with the palette colors of the index buffer.
+func (screen *Screen) resolveIndices() {
+	var lut [256]ColorRGB
+	copy(lut[:], screen.palette)
+	for i, index := range screen.indices {
+		screen.buffer[i] = lut[index]
+	}
+}
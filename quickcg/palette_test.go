@@ -0,0 +1,93 @@
+package quickcg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writePalette writes a palette fixture under the given file name.
+func writePalette(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPalette(t *testing.T) {
+	act := make([]byte, 772)
+	for i := range 256 {
+		act[i*3], act[i*3+1], act[i*3+2] = uint8(i), uint8(255-i), 7
+	}
+	act[768], act[769] = 0, 3
+
+	tests := []struct {
+		name string
+		data []byte
+		want Palette
+	}{
+		{"colors.gpl", []byte("GIMP Palette\nName: Test\nColumns: 4\n# a comment\n\n255 0 0\tRed\n  0 128 255 Sky blue\n#1 2 3\n"),
+			Palette{{R: 255}, {G: 128, B: 255}}},
+		{"colors.pal", []byte("JASC-PAL\r\n0100\r\n2\r\n1 2 3\r\n250 251 252\r\n"),
+			Palette{{R: 1, G: 2, B: 3}, {R: 250, G: 251, B: 252}}},
+		{"colors.act", act, Palette{{R: 0, G: 255, B: 7}, {R: 1, G: 254, B: 7}, {R: 2, G: 253, B: 7}}},
+		{"full.act", act[:768], nil},
+	}
+	for _, tt := range tests {
+		p, err := LoadPalette(writePalette(t, tt.name, tt.data))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if tt.name == "full.act" {
+			if len(p) != 256 || p[255] != (ColorRGB{R: 255, B: 7}) {
+				t.Errorf("%s: got %d colors ending in %v", tt.name, len(p), p[len(p)-1])
+			}
+			continue
+		}
+		if len(p) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, p, tt.want)
+			continue
+		}
+		for i := range p {
+			if p[i] != tt.want[i] {
+				t.Errorf("%s: color %d = %v, want %v", tt.name, i, p[i], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestLoadPaletteMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty.gpl", ""},
+		{"header.gpl", "Palette\n255 0 0\n"},
+		{"short.gpl", "GIMP Palette\n255 0\n"},
+		{"range.gpl", "GIMP Palette\n256 0 0\n"},
+		{"digits.gpl", "GIMP Palette\n12 x 0\n"},
+		{"empty.pal", ""},
+		{"header.pal", "RIFF-PAL\n0100\n1\n0 0 0\n"},
+		{"truncated.pal", "JASC-PAL\n0100\n3\n1 2 3\n4 5 6\n"},
+		{"extra.pal", "JASC-PAL\n0100\n1\n1 2 3\n4 5 6\n"},
+		{"count.pal", "JASC-PAL\n0100\nmany\n1 2 3\n"},
+		{"negative.pal", "JASC-PAL\n0100\n-1\n"},
+		{"range.pal", "JASC-PAL\n0100\n1\n1 2 300\n"},
+		{"truncated.act", strings.Repeat("\x00", 100)},
+		{"long.act", strings.Repeat("\x00", 800)},
+		{"colors.txt", "255 0 0\n"},
+	}
+	for _, tt := range tests {
+		if p, err := LoadPalette(writePalette(t, tt.name, []byte(tt.data))); err == nil {
+			t.Errorf("%s: got %v, want an error", tt.name, p)
+		}
+	}
+
+	if _, err := LoadPalette(filepath.Join(t.TempDir(), "missing.gpl")); err == nil {
+		t.Error("missing file loaded without an error")
+	}
+}
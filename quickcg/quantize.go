@@ -0,0 +1,327 @@
+package quickcg
+
+import (
+	"slices"
+)
+
+// colorCount is a distinct color together with the number of pixels using it.
+type colorCount struct {
+	c ColorRGB
+	n int
+}
+
+// colorHistogram returns the distinct colors of pixels with their counts,
+// sorted so that the result does not depend on map iteration order.
+func colorHistogram(pixels []ColorRGB) []colorCount {
+	hist := make(map[ColorRGB]int)
+	for _, c := range pixels {
+		hist[c]++
+	}
+
+	colors := make([]colorCount, 0, len(hist))
+	for c, n := range hist {
+		colors = append(colors, colorCount{c, n})
+	}
+	slices.SortFunc(colors, func(a, b colorCount) int {
+		return packRGB(a.c) - packRGB(b.c)
+	})
+
+	return colors
+}
+
+func packRGB(c ColorRGB) int {
+	return int(c.R)<<16 | int(c.G)<<8 | int(c.B)
+}
+
+func channel(c ColorRGB, ch int) uint8 {
+	switch ch {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	}
+	return c.B
+}
+
+// MedianCutPalette reduces pixels to a palette of at most n colors by
+// repeatedly splitting the color box with the widest channel range at its
+// population median. It returns an empty palette if n <= 0.
+func MedianCutPalette(pixels []ColorRGB, n int) Palette {
+	if n <= 0 {
+		return Palette{}
+	}
+	colors := colorHistogram(pixels)
+	if len(colors) <= n {
+		palette := make(Palette, len(colors))
+		for i, cc := range colors {
+			palette[i] = cc.c
+		}
+		return palette
+	}
+
+	boxes := [][]colorCount{colors}
+	for len(boxes) < n {
+		best, bestRange, bestChannel := -1, -1, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			r, ch := boxRange(box)
+			if r > bestRange {
+				best, bestRange, bestChannel = i, r, ch
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		box := boxes[best]
+		slices.SortStableFunc(box, func(a, b colorCount) int {
+			return int(channel(a.c, bestChannel)) - int(channel(b.c, bestChannel))
+		})
+
+		total := 0
+		for _, cc := range box {
+			total += cc.n
+		}
+		cut, acc := 1, 0
+		for i, cc := range box {
+			acc += cc.n
+			if acc*2 >= total {
+				cut = i + 1
+				break
+			}
+		}
+		cut = min(max(cut, 1), len(box)-1)
+
+		boxes[best] = box[:cut]
+		boxes = append(boxes, box[cut:])
+	}
+
+	palette := make(Palette, len(boxes))
+	for i, box := range boxes {
+		var r, g, b, total int
+		for _, cc := range box {
+			r += int(cc.c.R) * cc.n
+			g += int(cc.c.G) * cc.n
+			b += int(cc.c.B) * cc.n
+			total += cc.n
+		}
+		palette[i] = ColorRGB{
+			R: uint8((r + total/2) / total),
+			G: uint8((g + total/2) / total),
+			B: uint8((b + total/2) / total),
+		}
+	}
+
+	return palette
+}
+
+// boxRange returns the widest channel range of a color box and the channel it belongs to.
+func boxRange(box []colorCount) (int, int) {
+	lo := [3]int{255, 255, 255}
+	hi := [3]int{0, 0, 0}
+	for _, cc := range box {
+		for ch := range 3 {
+			v := int(channel(cc.c, ch))
+			lo[ch] = min(lo[ch], v)
+			hi[ch] = max(hi[ch], v)
+		}
+	}
+
+	best, ch := -1, 0
+	for i := range 3 {
+		if hi[i]-lo[i] > best {
+			best, ch = hi[i]-lo[i], i
+		}
+	}
+	return best, ch
+}
+
+// octreeNode is a node of the color octree used by OctreePalette.
+type octreeNode struct {
+	children [8]*octreeNode
+	r, g, b  int
+	count    int
+	leaf     bool
+}
+
+// OctreePalette reduces pixels to a palette of at most n colors by building
+// an 8-level color octree and merging the deepest nodes until n leaves remain.
+// It is faster than MedianCutPalette on images with many distinct colors.
+// It returns an empty palette if n <= 0.
+func OctreePalette(pixels []ColorRGB, n int) Palette {
+	if n <= 0 {
+		return Palette{}
+	}
+	root := &octreeNode{}
+	var levels [8][]*octreeNode
+	leaves := 0
+
+	for _, cc := range colorHistogram(pixels) {
+		node := root
+		for level := range 8 {
+			shift := 7 - level
+			idx := int(cc.c.R>>shift&1)<<2 | int(cc.c.G>>shift&1)<<1 | int(cc.c.B>>shift&1)
+			child := node.children[idx]
+			if child == nil {
+				child = &octreeNode{leaf: level == 7}
+				node.children[idx] = child
+				if child.leaf {
+					leaves++
+				} else {
+					levels[level+1] = append(levels[level+1], child)
+				}
+			}
+			node = child
+		}
+		node.r += int(cc.c.R) * cc.n
+		node.g += int(cc.c.G) * cc.n
+		node.b += int(cc.c.B) * cc.n
+		node.count += cc.n
+	}
+	levels[0] = []*octreeNode{root}
+
+	for level := 7; level >= 0 && leaves > n; level-- {
+		for len(levels[level]) > 0 && leaves > n {
+			last := len(levels[level]) - 1
+			node := levels[level][last]
+			levels[level] = levels[level][:last]
+
+			merged := 0
+			for i, child := range node.children {
+				if child == nil {
+					continue
+				}
+				node.r += child.r
+				node.g += child.g
+				node.b += child.b
+				node.count += child.count
+				node.children[i] = nil
+				merged++
+			}
+			node.leaf = true
+			leaves -= merged - 1
+		}
+	}
+
+	palette := make(Palette, 0, leaves)
+	var collect func(node *octreeNode)
+	collect = func(node *octreeNode) {
+		if node.leaf {
+			if node.count > 0 {
+				palette = append(palette, ColorRGB{
+					R: uint8(node.r / node.count),
+					G: uint8(node.g / node.count),
+					B: uint8(node.b / node.count),
+				})
+			}
+			return
+		}
+		for _, child := range node.children {
+			if child != nil {
+				collect(child)
+			}
+		}
+	}
+	collect(root)
+
+	return palette
+}
+
+// KMeansPalette refines a median-cut palette of at most n colors with up to
+// iterations rounds of k-means (Lloyd's algorithm), moving every entry to the
+// mean of the pixels closest to it. This lowers the average error, at the
+// cost of a pass over all distinct colors per round. It returns an empty
+// palette if n <= 0.
+func KMeansPalette(pixels []ColorRGB, n, iterations int) Palette {
+	colors := colorHistogram(pixels)
+	palette := MedianCutPalette(pixels, n)
+	if len(palette) == 0 || len(colors) <= n {
+		return palette
+	}
+
+	assigned := make([]int, len(colors))
+	for iter := range iterations {
+		changed := false
+		for i, cc := range colors {
+			nearest := palette.Nearest(cc.c)
+			if iter == 0 || nearest != assigned[i] {
+				assigned[i] = nearest
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([][4]int, len(palette))
+		for i, cc := range colors {
+			s := &sums[assigned[i]]
+			s[0] += int(cc.c.R) * cc.n
+			s[1] += int(cc.c.G) * cc.n
+			s[2] += int(cc.c.B) * cc.n
+			s[3] += cc.n
+		}
+		for k, s := range sums {
+			if total := s[3]; total > 0 {
+				palette[k] = ColorRGB{
+					R: uint8((s[0] + total/2) / total),
+					G: uint8((s[1] + total/2) / total),
+					B: uint8((s[2] + total/2) / total),
+				}
+			}
+		}
+	}
+
+	return palette
+}
+
+// Nearest returns the index of the palette entry closest to c by squared
+// RGB distance, or -1 if the palette is empty.
+func (p Palette) Nearest(c ColorRGB) int {
+	best, bestDist := -1, 0
+	for i, e := range p {
+		dr := int(c.R) - int(e.R)
+		dg := int(c.G) - int(e.G)
+		db := int(c.B) - int(e.B)
+		d := dr*dr + dg*dg + db*db
+		if best < 0 || d < bestDist {
+			best, bestDist = i, d
+			if d == 0 {
+				break
+			}
+		}
+	}
+	return best
+}
+
+// paletteMapper finds the nearest palette entry for a color, caching the results.
+type paletteMapper struct {
+	palette Palette
+	cache   map[ColorRGB]uint8
+}
+
+func newPaletteMapper(palette Palette) *paletteMapper {
+	return &paletteMapper{palette: palette, cache: make(map[ColorRGB]uint8)}
+}
+
+func (m *paletteMapper) index(c ColorRGB) uint8 {
+	if idx, ok := m.cache[c]; ok {
+		return idx
+	}
+
+	idx := uint8(max(m.palette.Nearest(c), 0))
+	m.cache[c] = idx
+	return idx
+}
+
+func clampUint8(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 255 {
+		return 255
+	}
+	return uint8(v + 0.5)
+}
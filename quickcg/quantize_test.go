@@ -0,0 +1,105 @@
+package quickcg
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// testPixels returns a reproducible image with many distinct colors.
+func testPixels() *Image {
+	rng := rand.New(rand.NewSource(1))
+	img := NewImage(64, 48)
+	for i := range img.Pixels {
+		x, y := i%img.W, i/img.W
+		img.Pixels[i] = ColorRGB{R: uint8(x * 4), G: uint8(y * 5), B: uint8(rng.Intn(256))}
+	}
+	return img
+}
+
+var quantizers = []struct {
+	name    string
+	palette func(pixels []ColorRGB, n int) Palette
+}{
+	{"median cut", MedianCutPalette},
+	{"octree", OctreePalette},
+	{"k-means", func(pixels []ColorRGB, n int) Palette { return KMeansPalette(pixels, n, 8) }},
+}
+
+func TestPaletteSize(t *testing.T) {
+	img := testPixels()
+	for _, q := range quantizers {
+		for _, n := range []int{-1, 0, 1, 2, 7, 16, 256} {
+			p := q.palette(img.Pixels, n)
+			if len(p) > max(n, 0) {
+				t.Errorf("%s with n = %d: got %d colors", q.name, n, len(p))
+			}
+			if n > 0 && len(p) == 0 {
+				t.Errorf("%s with n = %d: got an empty palette", q.name, n)
+			}
+		}
+	}
+}
+
+func TestPaletteExact(t *testing.T) {
+	colors := []ColorRGB{{R: 255}, {G: 255}, {B: 255}, {R: 10, G: 20, B: 30}, {R: 200, G: 200}}
+	img := NewImage(10, 10)
+	for i := range img.Pixels {
+		img.Pixels[i] = colors[(i*7)%len(colors)]
+	}
+
+	for _, q := range quantizers {
+		p := q.palette(img.Pixels, 8)
+		if len(p) != len(colors) {
+			t.Errorf("%s: got %d colors, want %d", q.name, len(p), len(colors))
+			continue
+		}
+		for _, dither := range []Dither{DitherNone, DitherBayer, DitherFloydSteinberg, DitherAtkinson, DitherBlueNoise} {
+			indices := MapToPalette(img, p, dither)
+			for i, idx := range indices {
+				if p[idx] != img.Pixels[i] {
+					t.Errorf("%s, dither %d: pixel %d maps to %v, want %v", q.name, dither, i, p[idx], img.Pixels[i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestPaletteDeterministic(t *testing.T) {
+	img := testPixels()
+	for _, q := range quantizers {
+		first := q.palette(img.Pixels, 16)
+		for range 3 {
+			again := q.palette(img.Clone().Pixels, 16)
+			if len(again) != len(first) {
+				t.Fatalf("%s: palette size changed from %d to %d", q.name, len(first), len(again))
+			}
+			for i := range first {
+				if again[i] != first[i] {
+					t.Fatalf("%s: color %d changed from %v to %v", q.name, i, first[i], again[i])
+				}
+			}
+		}
+	}
+
+	p := MedianCutPalette(img.Pixels, 16)
+	for _, dither := range []Dither{DitherNone, DitherBayer, DitherFloydSteinberg, DitherAtkinson, DitherBlueNoise} {
+		a, b := MapToPalette(img, p, dither), MapToPalette(img, p, dither)
+		for i := range a {
+			if a[i] != b[i] || int(a[i]) >= len(p) {
+				t.Fatalf("dither %d: pixel %d maps to %d and %d", dither, i, a[i], b[i])
+			}
+		}
+	}
+}
+
+func TestMapToPaletteNearest(t *testing.T) {
+	p := Palette{{}, {R: 255, G: 255, B: 255}}
+	img := NewImageFromPixels([]ColorRGB{{R: 10, G: 10, B: 10}, {R: 250, G: 240, B: 200}, {R: 100, G: 100, B: 100}}, 3, 1)
+	want := []uint8{0, 1, 0}
+	for i, idx := range MapToPalette(img, p, DitherNone) {
+		if idx != want[i] {
+			t.Errorf("pixel %d maps to %d, want %d", i, idx, want[i])
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package quickcg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"math"
+	"os"
+	"time"
+)
+
+// Quantizer selects the algorithm used to reduce a frame to a GIF palette.
+type Quantizer int
+
+const (
+	QuantizeMedianCut Quantizer = iota
+	QuantizeOctree
+	QuantizeKMeans
+)
+
+// RecordOptions configures StartRecording.
+type RecordOptions struct {
+	FPS       int       // frames captured per second (default 25)
+	Colors    int       // palette size per frame, 2 to 256 (default 256)
+	Quantizer Quantizer // palette reduction algorithm
+	Dither    Dither    // dithering applied when mapping to the palette
+	LoopCount int       // 0 loops forever, -1 plays once, n repeats n times
+}
+
+// recorder collects presented frames until StopRecording encodes them.
+type recorder struct {
+	file     *os.File
+	opts     RecordOptions
+	start    time.Time
+	last     time.Duration
+	frames   []*Image
+	captured []time.Duration
+}
+
+// StartRecording begins capturing every presented frame into an animated GIF
+// at path. Frames are taken in Redraw, at most opts.FPS times per second,
+// and timed when they are presented. Quantization is left to StopRecording,
+// so that recording does not slow down the frame loop.
+func (screen *Screen) StartRecording(path string, opts RecordOptions) error {
+	if screen.recorder != nil {
+		return fmt.Errorf("recording already in progress")
+	}
+
+	if opts.FPS <= 0 {
+		opts.FPS = 25
+	}
+	if opts.Colors <= 0 || opts.Colors > 256 {
+		opts.Colors = 256
+	}
+	opts.Colors = max(opts.Colors, 2)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	screen.recorder = &recorder{
+		file:  file,
+		opts:  opts,
+		start: time.Now(),
+		last:  -time.Hour,
+	}
+
+	return nil
+}
+
+// IsRecording reports whether a recording started with StartRecording is in progress.
+func (screen *Screen) IsRecording() bool {
+	return screen.recorder != nil
+}
+
+// StopRecording quantizes the captured frames and writes the animated GIF.
+// If no frame was captured, the file is removed and an error is returned.
+func (screen *Screen) StopRecording() error {
+	rec := screen.recorder
+	if rec == nil {
+		return fmt.Errorf("no recording in progress")
+	}
+	screen.recorder = nil
+
+	end := time.Since(rec.start)
+	anim := &gif.GIF{LoopCount: rec.opts.LoopCount}
+
+	for i, frame := range rec.frames {
+		// Delays are derived from rounded timestamps rather than rounded durations
+		// so that the rounding error does not accumulate over the animation.
+		next := end
+		if i+1 < len(rec.frames) {
+			next = rec.captured[i+1]
+		}
+		delay := centiseconds(next) - centiseconds(rec.captured[i])
+
+		anim.Image = append(anim.Image, rec.quantize(frame))
+		anim.Delay = append(anim.Delay, max(delay, 1))
+	}
+
+	if len(anim.Image) == 0 {
+		rec.discard()
+		return fmt.Errorf("no frames were recorded")
+	}
+
+	err := gif.EncodeAll(rec.file, anim)
+	if err != nil {
+		rec.discard()
+		return fmt.Errorf("failed to encode GIF: %w", err)
+	}
+
+	err = rec.file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	return nil
+}
+
+// discard closes and removes the output file, so that a failed recording
+// does not leave an invalid GIF behind.
+func (rec *recorder) discard() {
+	rec.file.Close()
+	os.Remove(rec.file.Name())
+}
+
+// quantize reduces a captured frame to a palette of at most opts.Colors colors.
+func (rec *recorder) quantize(frame *Image) *image.Paletted {
+	var palette Palette
+	switch rec.opts.Quantizer {
+	case QuantizeOctree:
+		palette = OctreePalette(frame.Pixels, rec.opts.Colors)
+	case QuantizeKMeans:
+		palette = KMeansPalette(frame.Pixels, rec.opts.Colors, 8)
+	default:
+		palette = MedianCutPalette(frame.Pixels, rec.opts.Colors)
+	}
+
+	paletted := image.NewPaletted(image.Rect(0, 0, frame.W, frame.H), toColorPalette(palette))
+	copy(paletted.Pix, MapToPalette(frame, palette, rec.opts.Dither))
+	return paletted
+}
+
+// recordFrame captures the presented frame if a recording is active and
+// enough time has passed since the previous capture.
+func (screen *Screen) recordFrame() {
+	rec := screen.recorder
+	if rec == nil {
+		return
+	}
+
+	now := time.Since(rec.start)
+	interval := time.Second / time.Duration(rec.opts.FPS)
+	if now-rec.last < interval {
+		return
+	}
+
+	rec.last = now
+	rec.frames = append(rec.frames, screen.Capture(CaptureOptions{Source: CaptureFrame, IncludeOverlays: true}))
+	rec.captured = append(rec.captured, now)
+}
+
+func centiseconds(d time.Duration) int {
+	return int(math.Round(d.Seconds() * 100))
+}
+
+func toColorPalette(palette []ColorRGB) color.Palette {
+	p := make(color.Palette, len(palette))
+	for i, c := range palette {
+		p[i] = color.RGBA{R: c.R, G: c.G, B: c.B, A: 255}
+	}
+	return p
+}
@@ -0,0 +1,422 @@
+package quickcg
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// Camera describes the view and projection used by Renderer3D.
+type Camera struct {
+	Position vecmath.Vec3
+	Target   vecmath.Vec3
+	Up       vecmath.Vec3
+	FOV      float64 // vertical field of view in radians
+	Near     float64 // distance of the near clipping plane
+	Far      float64 // distance of the far clipping plane
+}
+
+// NewCamera returns a camera at position looking at target, with +Y up,
+// a 60° field of view and clipping planes at 0.1 and 1000.
+func NewCamera(position, target vecmath.Vec3) Camera {
+	return Camera{
+		Position: position,
+		Target:   target,
+		Up:       vecmath.Vec3{Y: 1},
+		FOV:      math.Pi / 3,
+		Near:     0.1,
+		Far:      1000,
+	}
+}
+
+// View returns the world-to-camera transform.
+func (cam Camera) View() vecmath.Mat4 {
+	return vecmath.LookAt(cam.Position, cam.Target, cam.Up)
+}
+
+// Projection returns the perspective projection for the given width/height aspect ratio.
+func (cam Camera) Projection(aspect float64) vecmath.Mat4 {
+	return vecmath.Perspective(cam.FOV, aspect, cam.Near, cam.Far)
+}
+
+// Vertex3D is a corner of a triangle drawn by Renderer3D.
+type Vertex3D struct {
+	Pos    vecmath.Vec3 // position in model space
+	UV     vecmath.Vec2 // texture coordinates, (0, 0) is the top-left texel
+	Color  ColorRGB     // color used when the triangle has no texture
+	Normal vecmath.Vec3 // model-space normal; zero uses the face normal
+}
+
+// Renderer3D rasterizes triangles into the screen pixel buffer with a depth buffer.
+//
+// Triangles go through the Model transform and the Camera, are clipped against
+// the near and far planes and are filled with perspective-correct texture
+// coordinates and colors. Call DrawBuffer to show the result. The view is
+// fitted into the clip rectangle of the screen, so pushing a clip with
+// PushClip draws an inset view.
+//
+// Lighting is off by default. Set Shading and Lights to light the scene,
+// or Shader to compute every pixel with your own Go function.
+type Renderer3D struct {
+	Camera        Camera
+	Model         vecmath.Mat4 // model-to-world transform applied to every vertex
+	CullBackFaces bool         // skip triangles whose corners appear clockwise
+
+	Shading    ShadingMode
+	Lights     []Light
+	Specular   float64        // strength of specular highlights, 0 disables them
+	Shininess  float64        // specular exponent, higher values give smaller highlights
+	BlinnPhong bool           // use the Blinn-Phong half vector instead of the Phong reflection vector
+	Shader     FragmentShader // when set, replaces the built-in shading
+
+	DepthCue    *DepthCue // dims wireframe lines with distance; nil disables it
+	HiddenLines bool      // hide wireframe lines behind surfaces in the depth buffer
+
+	screen    *Screen
+	depth     []float64
+	depthOnly bool // rasterize into the depth buffer without touching the pixels
+}
+
+// rasterVertex carries a vertex and its interpolated attributes through clipping.
+type rasterVertex struct {
+	clip     vecmath.Vec4
+	uv       vecmath.Vec2
+	color    vecmath.Vec3
+	world    vecmath.Vec3
+	normal   vecmath.Vec3
+	diffuse  vecmath.Vec3 // flat and Gouraud lighting
+	specular vecmath.Vec3
+}
+
+// screenVertex is a rasterVertex after the perspective divide. Attributes are
+// premultiplied by invW so that they interpolate linearly in screen space.
+type screenVertex struct {
+	x, y, z  float64
+	invW     float64
+	uv       vecmath.Vec2
+	color    vecmath.Vec3
+	world    vecmath.Vec3
+	normal   vecmath.Vec3
+	diffuse  vecmath.Vec3
+	specular vecmath.Vec3
+}
+
+// NewRenderer3D creates a renderer drawing into the screen buffer.
+func NewRenderer3D(screen *Screen) *Renderer3D {
+	r := &Renderer3D{
+		Camera:        NewCamera(vecmath.Vec3{Z: 5}, vecmath.Vec3{}),
+		Model:         vecmath.Ident4(),
+		CullBackFaces: true,
+		Shininess:     32,
+		screen:        screen,
+		depth:         make([]float64, screen.w*screen.h),
+	}
+	r.ClearDepth()
+
+	return r
+}
+
+// Clear fills the clip rectangle of the screen buffer with color and resets
+// the depth buffer inside it.
+func (r *Renderer3D) Clear(color ColorRGB) {
+	clip := r.screen.clip
+	for y := clip.Y; y < clip.Y+clip.H; y++ {
+		for i := y*r.screen.w + clip.X; i < y*r.screen.w+clip.X+clip.W; i++ {
+			r.screen.buffer[i] = color
+			r.depth[i] = math.Inf(1)
+		}
+	}
+}
+
+// ClearDepth resets the depth buffer so that everything drawn next is visible.
+func (r *Renderer3D) ClearDepth() {
+	for i := range r.depth {
+		r.depth[i] = math.Inf(1)
+	}
+}
+
+// DepthAt returns the depth buffer value at screen pixel (x, y), from 0 at the near
+// plane to 1 at the far plane, or +Inf where nothing has been drawn.
+func (r *Renderer3D) DepthAt(x, y int) float64 {
+	if x < 0 || y < 0 || x >= r.screen.w || y >= r.screen.h {
+		return math.Inf(1)
+	}
+	return r.depth[y*r.screen.w+x]
+}
+
+// DrawTriangle draws a triangle with counter-clockwise front face.
+// With a non-nil texture the pixels are sampled from it using the vertex UVs,
+// otherwise the vertex colors are interpolated.
+func (r *Renderer3D) DrawTriangle(a, b, c Vertex3D, texture *Image) {
+	if r.screen.clip.Empty() {
+		return
+	}
+	view := r.Camera.View()
+	projection := r.Camera.Projection(r.aspect())
+
+	corners := [3]Vertex3D{a, b, c}
+	var world, eye [3]vecmath.Vec3
+	for i, v := range corners {
+		world[i] = r.Model.MulPoint(v.Pos)
+		eye[i] = view.MulPoint(world[i])
+	}
+
+	if r.CullBackFaces {
+		// The camera sits at the origin of view space.
+		normal := eye[1].Sub(eye[0]).Cross(eye[2].Sub(eye[0]))
+		if normal.Dot(eye[0]) >= 0 {
+			return
+		}
+	}
+
+	faceNormal := world[1].Sub(world[0]).Cross(world[2].Sub(world[0])).Normalize()
+	normalMatrix := r.normalMatrix()
+
+	var flatDiffuse, flatSpecular vecmath.Vec3
+	if r.Shading == ShadeFlat {
+		centroid := world[0].Add(world[1]).Add(world[2]).Scale(1.0 / 3)
+		flatDiffuse, flatSpecular = r.lighting(centroid, faceNormal)
+	}
+
+	poly := make([]rasterVertex, 3)
+	for i, v := range corners {
+		normal := faceNormal
+		if v.Normal != (vecmath.Vec3{}) {
+			normal = normalMatrix.MulVec3(v.Normal).Normalize()
+		}
+
+		rv := rasterVertex{
+			clip:     projection.MulVec4(eye[i].Vec4(1)),
+			uv:       v.UV,
+			color:    colorVec(v.Color),
+			world:    world[i],
+			normal:   normal,
+			diffuse:  flatDiffuse,
+			specular: flatSpecular,
+		}
+		if r.Shading == ShadeGouraud {
+			rv.diffuse, rv.specular = r.lighting(world[i], normal)
+		}
+		poly[i] = rv
+	}
+	r.drawPolygon(poly, texture)
+}
+
+// aspect returns the width/height ratio of the viewport, the clip rectangle.
+func (r *Renderer3D) aspect() float64 {
+	return float64(r.screen.clip.W) / float64(r.screen.clip.H)
+}
+
+// normalMatrix returns the transform for normals, the inverse transpose of Model.
+func (r *Renderer3D) normalMatrix() vecmath.Mat3 {
+	inv, _ := r.Model.Mat3().Inverse()
+	return inv.Transpose()
+}
+
+// DrawTriangles draws a list of triangles, three vertices each.
+func (r *Renderer3D) DrawTriangles(vertices []Vertex3D, texture *Image) {
+	for i := 0; i+2 < len(vertices); i += 3 {
+		r.DrawTriangle(vertices[i], vertices[i+1], vertices[i+2], texture)
+	}
+}
+
+// drawPolygon clips a convex clip-space polygon against the near and far
+// planes and rasterizes it as a triangle fan.
+func (r *Renderer3D) drawPolygon(poly []rasterVertex, texture *Image) {
+	poly = clipRasterPolygon(poly, func(v rasterVertex) float64 { return v.clip.Z + v.clip.W })
+	poly = clipRasterPolygon(poly, func(v rasterVertex) float64 { return v.clip.W - v.clip.Z })
+	if len(poly) < 3 {
+		return
+	}
+	if texture != nil && (texture.W == 0 || texture.H == 0) {
+		// An empty texture has nothing to sample; use the vertex colors.
+		texture = nil
+	}
+
+	verts := make([]screenVertex, len(poly))
+	for i, v := range poly {
+		verts[i] = r.toScreen(v)
+	}
+	for i := 1; i+1 < len(verts); i++ {
+		r.rasterize(verts[0], verts[i], verts[i+1], texture)
+	}
+}
+
+// clipRasterPolygon keeps the part of poly where dist is non-negative
+// (Sutherland–Hodgman against a single plane).
+func clipRasterPolygon(poly []rasterVertex, dist func(rasterVertex) float64) []rasterVertex {
+	if len(poly) == 0 {
+		return poly
+	}
+
+	out := make([]rasterVertex, 0, len(poly)+1)
+	for i, cur := range poly {
+		next := poly[(i+1)%len(poly)]
+		dc, dn := dist(cur), dist(next)
+		if dc >= 0 {
+			out = append(out, cur)
+		}
+		if (dc >= 0) != (dn >= 0) {
+			out = append(out, lerpRasterVertex(cur, next, dc/(dc-dn)))
+		}
+	}
+	return out
+}
+
+func lerpRasterVertex(a, b rasterVertex, t float64) rasterVertex {
+	return rasterVertex{
+		clip:     a.clip.Lerp(b.clip, t),
+		uv:       a.uv.Lerp(b.uv, t),
+		color:    a.color.Lerp(b.color, t),
+		world:    a.world.Lerp(b.world, t),
+		normal:   a.normal.Lerp(b.normal, t),
+		diffuse:  a.diffuse.Lerp(b.diffuse, t),
+		specular: a.specular.Lerp(b.specular, t),
+	}
+}
+
+// toScreen applies the perspective divide and the viewport transform, which
+// maps the view onto the clip rectangle.
+func (r *Renderer3D) toScreen(v rasterVertex) screenVertex {
+	invW := 1 / v.clip.W
+	vp := r.screen.clip
+	return screenVertex{
+		x:        float64(vp.X) + (v.clip.X*invW+1)/2*float64(vp.W),
+		y:        float64(vp.Y) + (1-v.clip.Y*invW)/2*float64(vp.H),
+		z:        (v.clip.Z*invW + 1) / 2,
+		invW:     invW,
+		uv:       v.uv.Scale(invW),
+		color:    v.color.Scale(invW),
+		world:    v.world.Scale(invW),
+		normal:   v.normal.Scale(invW),
+		diffuse:  v.diffuse.Scale(invW),
+		specular: v.specular.Scale(invW),
+	}
+}
+
+// rasterize fills a screen-space triangle, sampling pixel centers.
+func (r *Renderer3D) rasterize(v0, v1, v2 screenVertex, texture *Image) {
+	area := edgeFunction(v0, v1, v2.x, v2.y)
+	if area == 0 {
+		return
+	}
+
+	// Depth-only passes are pushed back by the depth change across one pixel,
+	// so that lines along the surface are not hidden by the surface itself.
+	var depthOffset float64
+	if r.depthOnly {
+		dzdx := ((v1.z-v0.z)*(v2.y-v0.y) - (v2.z-v0.z)*(v1.y-v0.y)) / area
+		dzdy := ((v1.x-v0.x)*(v2.z-v0.z) - (v2.x-v0.x)*(v1.z-v0.z)) / area
+		depthOffset = math.Abs(dzdx) + math.Abs(dzdy) + 1e-7
+	}
+
+	w, clip := r.screen.w, r.screen.clip
+	minX := max(int(math.Floor(min(v0.x, v1.x, v2.x))), clip.X)
+	maxX := min(int(math.Ceil(max(v0.x, v1.x, v2.x))), clip.X+clip.W-1)
+	minY := max(int(math.Floor(min(v0.y, v1.y, v2.y))), clip.Y)
+	maxY := min(int(math.Ceil(max(v0.y, v1.y, v2.y))), clip.Y+clip.H-1)
+
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+
+			b0 := edgeFunction(v1, v2, px, py) / area
+			b1 := edgeFunction(v2, v0, px, py) / area
+			b2 := 1 - b0 - b1
+			if b0 < 0 || b1 < 0 || b2 < 0 {
+				continue
+			}
+
+			i := y*w + x
+			z := b0*v0.z + b1*v1.z + b2*v2.z
+			if z < 0 || z >= r.depth[i] {
+				continue
+			}
+			if r.depthOnly {
+				r.depth[i] = z + depthOffset
+				continue
+			}
+
+			// Undo the invW premultiplication of the interpolated attributes.
+			k := 1 / (b0*v0.invW + b1*v1.invW + b2*v2.invW)
+			w0, w1, w2 := b0*k, b1*k, b2*k
+
+			var base ColorRGB
+			uv := v0.uv.Scale(w0).Add(v1.uv.Scale(w1)).Add(v2.uv.Scale(w2))
+			if texture != nil {
+				base = sampleTexture(texture, uv)
+			} else {
+				base = vecColor(blend3(v0.color, v1.color, v2.color, w0, w1, w2))
+			}
+
+			var color ColorRGB
+			switch {
+			case r.Shader != nil:
+				color = r.Shader(Fragment{
+					X:        x,
+					Y:        y,
+					Depth:    z,
+					Position: blend3(v0.world, v1.world, v2.world, w0, w1, w2),
+					Normal:   blend3(v0.normal, v1.normal, v2.normal, w0, w1, w2).Normalize(),
+					UV:       uv,
+					Color:    base,
+					Texture:  texture,
+				})
+			case r.Shading == ShadePhong:
+				world := blend3(v0.world, v1.world, v2.world, w0, w1, w2)
+				normal := blend3(v0.normal, v1.normal, v2.normal, w0, w1, w2).Normalize()
+				diffuse, specular := r.lighting(world, normal)
+				color = vecColor(colorVec(base).Mul(diffuse).Add(specular))
+			case r.Shading == ShadeFlat || r.Shading == ShadeGouraud:
+				diffuse := blend3(v0.diffuse, v1.diffuse, v2.diffuse, w0, w1, w2)
+				specular := blend3(v0.specular, v1.specular, v2.specular, w0, w1, w2)
+				color = vecColor(colorVec(base).Mul(diffuse).Add(specular))
+			default:
+				color = base
+			}
+
+			r.depth[i] = z
+			r.screen.buffer[i] = color
+		}
+	}
+}
+
+// blend3 returns the weighted sum of three vectors.
+func blend3(a, b, c vecmath.Vec3, wa, wb, wc float64) vecmath.Vec3 {
+	return vecmath.Vec3{
+		X: a.X*wa + b.X*wb + c.X*wc,
+		Y: a.Y*wa + b.Y*wb + c.Y*wc,
+		Z: a.Z*wa + b.Z*wb + c.Z*wc,
+	}
+}
+
+// edgeFunction returns twice the signed area of the triangle (a, b, p).
+func edgeFunction(a, b screenVertex, px, py float64) float64 {
+	return (b.x-a.x)*(py-a.y) - (b.y-a.y)*(px-a.x)
+}
+
+// sampleTexture returns the texel at uv, repeating the texture outside [0, 1).
+func sampleTexture(texture *Image, uv vecmath.Vec2) ColorRGB {
+	tx := int(math.Floor(uv.X * float64(texture.W)))
+	ty := int(math.Floor(uv.Y * float64(texture.H)))
+	tx %= texture.W
+	ty %= texture.H
+	if tx < 0 {
+		tx += texture.W
+	}
+	if ty < 0 {
+		ty += texture.H
+	}
+	return texture.Pixels[ty*texture.W+tx]
+}
+
+// colorVec converts a color to a vector with channels in [0, 1].
+func colorVec(c ColorRGB) vecmath.Vec3 {
+	return vecmath.Vec3{X: float64(c.R) / 255, Y: float64(c.G) / 255, Z: float64(c.B) / 255}
+}
+
+// vecColor converts a vector with channels in [0, 1] to a color, clamping out-of-range values.
+func vecColor(v vecmath.Vec3) ColorRGB {
+	return ColorRGB{R: clampUint8(v.X * 255), G: clampUint8(v.Y * 255), B: clampUint8(v.Z * 255)}
+}
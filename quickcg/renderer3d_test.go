@@ -0,0 +1,64 @@
+package quickcg
+
+import (
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+func TestRenderer3DClip(t *testing.T) {
+	screen := NewHeadlessScreen(80, 60)
+	background := ColorRGB{R: 1, G: 2, B: 3}
+	for i := range screen.buffer {
+		screen.buffer[i] = background
+	}
+
+	r := NewRenderer3D(screen)
+	r.CullBackFaces = false
+	screen.PushTranslate(20, 10)
+	screen.PushClip(Rect{W: 30, H: 20})
+	r.Clear(ColorRGB{B: 100})
+
+	// A triangle covering the left half of the view, whatever its aspect.
+	red := ColorRGB{R: 255}
+	r.DrawTriangle(
+		Vertex3D{Pos: vecmath.Vec3{X: -100, Y: -100}, Color: red},
+		Vertex3D{Pos: vecmath.Vec3{Y: -100}, Color: red},
+		Vertex3D{Pos: vecmath.Vec3{Y: 100}, Color: red}, nil)
+	r.DrawTriangle(
+		Vertex3D{Pos: vecmath.Vec3{X: -100, Y: -100}, Color: red},
+		Vertex3D{Pos: vecmath.Vec3{Y: 100}, Color: red},
+		Vertex3D{Pos: vecmath.Vec3{X: -100, Y: 100}, Color: red}, nil)
+
+	inset := Rect{X: 20, Y: 10, W: 30, H: 20}
+	for y := range screen.h {
+		for x := range screen.w {
+			want := background
+			if inset.Contains(x, y) {
+				want = ColorRGB{B: 100}
+				if x < inset.X+inset.W/2 {
+					want = red
+				}
+			}
+			if got := screen.buffer[y*screen.w+x]; got != want {
+				t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestRenderer3DEmptyTexture(t *testing.T) {
+	screen := NewHeadlessScreen(20, 20)
+	r := NewRenderer3D(screen)
+	r.CullBackFaces = false
+
+	red := ColorRGB{R: 255}
+	r.DrawTriangle(
+		Vertex3D{Pos: vecmath.Vec3{X: -100, Y: -100}, Color: red},
+		Vertex3D{Pos: vecmath.Vec3{X: 100, Y: -100}, Color: red},
+		Vertex3D{Pos: vecmath.Vec3{Y: 100}, Color: red}, NewImage(0, 0))
+
+	if got := screen.buffer[10*screen.w+10]; got != red {
+		t.Errorf("center pixel = %v, want the vertex color %v", got, red)
+	}
+}
@@ -16,6 +16,12 @@ type ColorRGB struct {
 	R, G, B uint8
 }
 
+// ColorF represents an sRGB color with float64 channels, for intermediate
+// math that should not round or clamp at every step.
+type ColorF struct {
+	R, G, B float64 // range [0,1]
+}
+
 // ColorHSL represents a color in the HSL color model (Hue, Saturation, Lightness).
 type ColorHSL struct {
 	H, S, L float64 // range [0,1]
@@ -26,6 +32,43 @@ type ColorHSV struct {
 	H, S, V float64 // range [0,1]
 }
 
+// ColorLinearRGB represents an sRGB color with the gamma curve removed,
+// so that channel values are proportional to light intensity.
+type ColorLinearRGB struct {
+	R, G, B float64 // range [0,1]
+}
+
+// ColorXYZ represents a color in the CIE 1931 XYZ color space (D65 white point).
+type ColorXYZ struct {
+	X, Y, Z float64 // Y is the relative luminance, 1 for white
+}
+
+// ColorLab represents a color in the CIELAB color space (D65 white point).
+type ColorLab struct {
+	L    float64 // lightness, range [0,100]
+	A, B float64 // green–red and blue–yellow axes, roughly [-128,127]
+}
+
+// ColorLCh represents a CIELAB color in cylindrical form.
+type ColorLCh struct {
+	L float64 // lightness, range [0,100]
+	C float64 // chroma
+	H float64 // hue angle in degrees, range [0,360)
+}
+
+// ColorOKLab represents a color in Björn Ottosson's OKLab color space.
+type ColorOKLab struct {
+	L    float64 // lightness, range [0,1]
+	A, B float64 // green–red and blue–yellow axes, roughly [-0.4,0.4]
+}
+
+// ColorOKLCh represents an OKLab color in cylindrical form.
+type ColorOKLCh struct {
+	L float64 // lightness, range [0,1]
+	C float64 // chroma
+	H float64 // hue angle in degrees, range [0,360)
+}
+
 // Screen represents an SDL-based window and rendering context.
 // It encapsulates the state required to draw, handle events, and interact with a single window.
 type Screen struct {
@@ -37,6 +80,38 @@ type Screen struct {
 	w, h           int           // window width and height in pixels
 	buffer []ColorRGB            // logic pixel buffer
 	texture *sdl.Texture         // SDL-texture for output
+
+	frame         []ColorRGB // CPU copy of the render target, including overlays
+	base          []ColorRGB // render target contents without immediate-mode overlays
+	overlay       []bool     // pixels drawn in immediate mode since the last DrawBuffer or Fill
+	presented     []ColorRGB // frame as of the last Redraw
+	presentedBase []ColorRGB // base as of the last Redraw
+
+	clip        Rect     // drawing is restricted to this part of the screen, in screen coordinates
+	clipStack   []Rect   // clip rectangles saved by PushClip
+	ox, oy      int      // screen position of drawing coordinates (0, 0), set by PushTranslate
+	originStack [][2]int // origins saved by PushTranslate
+
+	indexed bool    // DrawBuffer resolves indices through palette
+	indices []uint8 // palette index per pixel, used in indexed mode
+	palette Palette // colors of the indices
+
+	recorder *recorder // active GIF recording, if any
+}
+
+// Rect is an axis-aligned rectangle with its top-left corner at (X, Y).
+type Rect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+// Image is an RGB pixel buffer stored in row-major order,
+// the same layout LoadPNG returns and the screen buffer uses.
+type Image struct {
+	Pixels []ColorRGB
+	W, H   int
 }
 
 var (
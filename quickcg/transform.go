@@ -0,0 +1,302 @@
+package quickcg
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// ResampleFilter selects how Resize, Rotate and Warp compute pixels that fall
+// between those of the source image.
+type ResampleFilter int
+
+const (
+	// ResampleNearest takes the closest source pixel: fast and blocky.
+	ResampleNearest ResampleFilter = iota
+	// ResampleBilinear blends the 2x2 nearest source pixels.
+	ResampleBilinear
+	// ResampleBicubic blends 4x4 source pixels with the Catmull-Rom spline,
+	// which is sharper than bilinear.
+	ResampleBicubic
+	// ResampleLanczos blends 6x6 source pixels with the Lanczos-3 window,
+	// the sharpest filter, with slight ringing at hard edges.
+	ResampleLanczos
+)
+
+// support returns the radius of the filter kernel in source pixels.
+func (f ResampleFilter) support() float64 {
+	switch f {
+	case ResampleBilinear:
+		return 1
+	case ResampleBicubic:
+		return 2
+	case ResampleLanczos:
+		return 3
+	}
+	return 0.5
+}
+
+// weight evaluates the filter kernel at distance x.
+func (f ResampleFilter) weight(x float64) float64 {
+	x = math.Abs(x)
+	switch f {
+	case ResampleBilinear:
+		return math.Max(0, 1-x)
+	case ResampleBicubic:
+		// Catmull-Rom, the cubic convolution kernel with a = -0.5.
+		if x < 1 {
+			return 1.5*x*x*x - 2.5*x*x + 1
+		}
+		if x < 2 {
+			return -0.5*x*x*x + 2.5*x*x - 4*x + 2
+		}
+		return 0
+	case ResampleLanczos:
+		if x == 0 {
+			return 1
+		}
+		if x >= 3 {
+			return 0
+		}
+		px := math.Pi * x
+		return 3 * math.Sin(px) * math.Sin(px/3) / (px * px)
+	}
+	if x < 0.5 {
+		return 1
+	}
+	return 0
+}
+
+// FlipH returns a copy of the image mirrored left to right.
+func (img *Image) FlipH() *Image {
+	out := NewImage(img.W, img.H)
+	for y := range img.H {
+		row := img.Pixels[y*img.W : (y+1)*img.W]
+		for x, c := range row {
+			out.Pixels[y*img.W+img.W-1-x] = c
+		}
+	}
+	return out
+}
+
+// FlipV returns a copy of the image mirrored top to bottom.
+func (img *Image) FlipV() *Image {
+	out := NewImage(img.W, img.H)
+	for y := range img.H {
+		copy(out.Pixels[(img.H-1-y)*img.W:(img.H-y)*img.W], img.Pixels[y*img.W:(y+1)*img.W])
+	}
+	return out
+}
+
+// Rotate90 returns a copy of the image rotated clockwise by the given number
+// of quarter turns; negative turns rotate counter-clockwise.
+func (img *Image) Rotate90(turns int) *Image {
+	turns = (turns%4 + 4) % 4
+	if turns == 0 {
+		return img.Clone()
+	}
+
+	w, h := img.W, img.H
+	if turns != 2 {
+		w, h = h, w
+	}
+	out := NewImage(w, h)
+	for y := range img.H {
+		for x := range img.W {
+			var dx, dy int
+			switch turns {
+			case 1:
+				dx, dy = img.H-1-y, x
+			case 2:
+				dx, dy = img.W-1-x, img.H-1-y
+			case 3:
+				dx, dy = y, img.W-1-x
+			}
+			out.Pixels[dy*w+dx] = img.Pixels[y*img.W+x]
+		}
+	}
+	return out
+}
+
+// Resize returns a copy of the image scaled to w x h pixels. When shrinking,
+// the filter is widened to cover every source pixel, so fine detail averages
+// out instead of aliasing.
+func (img *Image) Resize(w, h int, filter ResampleFilter) *Image {
+	out := NewImage(max(w, 0), max(h, 0))
+	if out.W == 0 || out.H == 0 || img.W == 0 || img.H == 0 {
+		return out
+	}
+
+	if filter == ResampleNearest {
+		for y := range out.H {
+			sy := min(int((float64(y)+0.5)*float64(img.H)/float64(out.H)), img.H-1)
+			for x := range out.W {
+				sx := min(int((float64(x)+0.5)*float64(img.W)/float64(out.W)), img.W-1)
+				out.Pixels[y*out.W+x] = img.Pixels[sy*img.W+sx]
+			}
+		}
+		return out
+	}
+
+	// Resample the rows to the new width, then the columns to the new height.
+	cols := resampleWeights(img.W, out.W, filter)
+	rows := resampleWeights(img.H, out.H, filter)
+
+	tmp := make([][3]float64, out.W*img.H)
+	for y := range img.H {
+		src := img.Pixels[y*img.W : (y+1)*img.W]
+		for x, taps := range cols {
+			var sum [3]float64
+			for i, wt := range taps.weights {
+				c := src[taps.index[i]]
+				sum[0] += wt * float64(c.R)
+				sum[1] += wt * float64(c.G)
+				sum[2] += wt * float64(c.B)
+			}
+			tmp[y*out.W+x] = sum
+		}
+	}
+
+	for y, taps := range rows {
+		for x := range out.W {
+			var sum [3]float64
+			for i, wt := range taps.weights {
+				c := tmp[taps.index[i]*out.W+x]
+				sum[0] += wt * c[0]
+				sum[1] += wt * c[1]
+				sum[2] += wt * c[2]
+			}
+			out.Pixels[y*out.W+x] = ColorRGB{R: clampUint8(sum[0]), G: clampUint8(sum[1]), B: clampUint8(sum[2])}
+		}
+	}
+	return out
+}
+
+// resampleTaps lists the source pixels and normalized weights of one output pixel.
+type resampleTaps struct {
+	index   []int
+	weights []float64
+}
+
+// resampleWeights computes the taps of every output pixel when resampling
+// srcN pixels to dstN, clamping taps beyond the border to the edge pixel.
+func resampleWeights(srcN, dstN int, filter ResampleFilter) []resampleTaps {
+	scale := float64(dstN) / float64(srcN)
+	stretch := math.Max(1, 1/scale)
+	support := filter.support() * stretch
+
+	taps := make([]resampleTaps, dstN)
+	for i := range taps {
+		center := (float64(i) + 0.5) / scale
+		lo := int(math.Floor(center - support))
+		hi := int(math.Ceil(center + support))
+
+		t := &taps[i]
+		sum := 0.0
+		for j := lo; j <= hi; j++ {
+			wt := filter.weight((float64(j) + 0.5 - center) / stretch)
+			if wt == 0 {
+				continue
+			}
+			t.index = append(t.index, min(max(j, 0), srcN-1))
+			t.weights = append(t.weights, wt)
+			sum += wt
+		}
+		for k := range t.weights {
+			t.weights[k] /= sum
+		}
+	}
+	return taps
+}
+
+// Rotate returns a copy of the image rotated clockwise by angle radians.
+// The result is enlarged to hold the whole rotated image, and the corners it
+// does not cover are filled with background.
+func (img *Image) Rotate(angle float64, background ColorRGB, filter ResampleFilter) *Image {
+	s, c := math.Sincos(angle)
+	w := int(math.Ceil(math.Abs(float64(img.W)*c) + math.Abs(float64(img.H)*s) - 1e-9))
+	h := int(math.Ceil(math.Abs(float64(img.W)*s) + math.Abs(float64(img.H)*c) - 1e-9))
+
+	// In y-down image coordinates Rotate2D turns clockwise.
+	m := vecmath.Translate2D(float64(w)/2, float64(h)/2).
+		Mul(vecmath.Rotate2D(angle)).
+		Mul(vecmath.Translate2D(-float64(img.W)/2, -float64(img.H)/2))
+	return img.Warp(m, w, h, background, filter)
+}
+
+// Warp returns a w x h image showing the source image transformed by m, a
+// homogeneous 2D transform from source to destination coordinates. Affine
+// matrices move, rotate, scale and shear the image; projective ones, such as
+// those from vecmath.Homography2D, map it onto any quadrilateral. Pixel (x, y)
+// covers the square from (x, y) to (x+1, y+1). Destination pixels that map
+// outside the source are filled with background.
+func (img *Image) Warp(m vecmath.Mat3, w, h int, background ColorRGB, filter ResampleFilter) *Image {
+	out := NewImage(max(w, 0), max(h, 0))
+	inv, ok := m.Inverse()
+	if !ok {
+		for i := range out.Pixels {
+			out.Pixels[i] = background
+		}
+		return out
+	}
+
+	for y := range out.H {
+		for x := range out.W {
+			p := inv.MulVec3(vecmath.Vec3{X: float64(x) + 0.5, Y: float64(y) + 0.5, Z: 1})
+			if p.Z <= 0 {
+				// Behind the horizon of a projective transform.
+				out.Pixels[y*out.W+x] = background
+				continue
+			}
+			out.Pixels[y*out.W+x] = img.Sample(p.X/p.Z, p.Y/p.Z, background, filter)
+		}
+	}
+	return out
+}
+
+// Sample returns the color at the point (x, y) of the image, where pixel
+// (i, j) covers the square from (i, j) to (i+1, j+1). Filter taps outside the
+// image read background, which smooths the outline of warped images.
+func (img *Image) Sample(x, y float64, background ColorRGB, filter ResampleFilter) ColorRGB {
+	at := func(i, j int) ColorRGB {
+		if i < 0 || j < 0 || i >= img.W || j >= img.H {
+			return background
+		}
+		return img.Pixels[j*img.W+i]
+	}
+
+	if filter == ResampleNearest {
+		return at(int(math.Floor(x)), int(math.Floor(y)))
+	}
+
+	// Pixel centers sit at half-integer coordinates.
+	x -= 0.5
+	y -= 0.5
+	support := filter.support()
+	x0, x1 := int(math.Floor(x-support))+1, int(math.Floor(x+support))
+	y0, y1 := int(math.Floor(y-support))+1, int(math.Floor(y+support))
+
+	var sum [3]float64
+	total := 0.0
+	for j := y0; j <= y1; j++ {
+		wy := filter.weight(float64(j) - y)
+		if wy == 0 {
+			continue
+		}
+		for i := x0; i <= x1; i++ {
+			wt := wy * filter.weight(float64(i)-x)
+			if wt == 0 {
+				continue
+			}
+			c := at(i, j)
+			sum[0] += wt * float64(c.R)
+			sum[1] += wt * float64(c.G)
+			sum[2] += wt * float64(c.B)
+			total += wt
+		}
+	}
+	if total == 0 {
+		return at(int(math.Floor(x+0.5)), int(math.Floor(y+0.5)))
+	}
+	return ColorRGB{R: clampUint8(sum[0] / total), G: clampUint8(sum[1] / total), B: clampUint8(sum[2] / total)}
+}
@@ -0,0 +1,164 @@
+package quickcg
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// DepthCue dims wireframe lines with their distance from the camera.
+type DepthCue struct {
+	Near float64 // view distance where dimming starts
+	Far  float64 // view distance where dimming reaches Min
+	Min  float64 // brightness at Far and beyond, in [0, 1]
+}
+
+// brightness returns the color scale for a point at view distance d.
+func (cue *DepthCue) brightness(d float64) float64 {
+	if cue == nil || d <= cue.Near {
+		return 1
+	}
+	if d >= cue.Far {
+		return cue.Min
+	}
+	t := (d - cue.Near) / (cue.Far - cue.Near)
+	return 1 + (cue.Min-1)*t
+}
+
+// DrawLine3D draws a line between two model-space points into the screen buffer.
+//
+// The segment is clipped against the view frustum, including the near and far
+// planes. With DepthCue set the line fades with distance, and with HiddenLines
+// set pixels behind surfaces already in the depth buffer are skipped.
+// Lines never write to the depth buffer.
+func (r *Renderer3D) DrawLine3D(a, b vecmath.Vec3, color ColorRGB) {
+	if r.screen.clip.Empty() {
+		return
+	}
+	mvp := r.Camera.Projection(r.aspect()).Mul(r.Camera.View()).Mul(r.Model)
+	ca := mvp.MulVec4(a.Vec4(1))
+	cb := mvp.MulVec4(b.Vec4(1))
+
+	t0, t1, ok := clipSegmentToFrustum(ca, cb)
+	if !ok {
+		return
+	}
+	p0 := r.toScreen(rasterVertex{clip: ca.Lerp(cb, t0)})
+	p1 := r.toScreen(rasterVertex{clip: ca.Lerp(cb, t1)})
+
+	steps := int(math.Ceil(max(math.Abs(p1.x-p0.x), math.Abs(p1.y-p0.y))))
+	for k := 0; k <= steps; k++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(k) / float64(steps)
+		}
+
+		x := int(math.Floor(p0.x + (p1.x-p0.x)*t))
+		y := int(math.Floor(p0.y + (p1.y-p0.y)*t))
+		if !r.screen.clip.Contains(x, y) {
+			continue
+		}
+
+		i := y*r.screen.w + x
+		if r.HiddenLines && p0.z+(p1.z-p0.z)*t > r.depth[i] {
+			continue
+		}
+
+		c := color
+		if r.DepthCue != nil {
+			// invW interpolates linearly in screen space; its inverse is the view distance.
+			dist := 1 / (p0.invW + (p1.invW-p0.invW)*t)
+			c = vecColor(colorVec(color).Scale(r.DepthCue.brightness(dist)))
+		}
+		r.screen.buffer[i] = c
+	}
+}
+
+// clipSegmentToFrustum clips the clip-space segment a-b against the six
+// planes -w <= x, y, z <= w (Liang–Barsky). It returns the parameter range
+// of the visible part, or false if the segment lies outside the frustum.
+func clipSegmentToFrustum(a, b vecmath.Vec4) (float64, float64, bool) {
+	t0, t1 := 0.0, 1.0
+	planes := [6]func(v vecmath.Vec4) float64{
+		func(v vecmath.Vec4) float64 { return v.W + v.X },
+		func(v vecmath.Vec4) float64 { return v.W - v.X },
+		func(v vecmath.Vec4) float64 { return v.W + v.Y },
+		func(v vecmath.Vec4) float64 { return v.W - v.Y },
+		func(v vecmath.Vec4) float64 { return v.W + v.Z },
+		func(v vecmath.Vec4) float64 { return v.W - v.Z },
+	}
+
+	for _, dist := range planes {
+		da, db := dist(a), dist(b)
+		if da < 0 && db < 0 {
+			return 0, 0, false
+		}
+		if da < 0 {
+			t0 = max(t0, da/(da-db))
+		} else if db < 0 {
+			t1 = min(t1, da/(da-db))
+		}
+		if t0 > t1 {
+			return 0, 0, false
+		}
+	}
+
+	return t0, t1, true
+}
+
+// DrawBox3D draws the edges of the axis-aligned box between two model-space corners.
+func (r *Renderer3D) DrawBox3D(lo, hi vecmath.Vec3, color ColorRGB) {
+	corner := func(i int) vecmath.Vec3 {
+		c := lo
+		if i&1 != 0 {
+			c.X = hi.X
+		}
+		if i&2 != 0 {
+			c.Y = hi.Y
+		}
+		if i&4 != 0 {
+			c.Z = hi.Z
+		}
+		return c
+	}
+
+	for i := range 8 {
+		for _, bit := range []int{1, 2, 4} {
+			if i&bit == 0 {
+				r.DrawLine3D(corner(i), corner(i|bit), color)
+			}
+		}
+	}
+}
+
+// DrawWireframe draws every edge of the mesh once.
+//
+// With r.HiddenLines set, the triangles are first rendered into the depth
+// buffer only, slightly pushed back, so that edges behind the mesh itself are
+// removed while its visible edges stay intact.
+func (mesh *Mesh) DrawWireframe(r *Renderer3D, color ColorRGB) {
+	if r.HiddenLines {
+		r.depthOnly = true
+		mesh.Draw(r)
+		r.depthOnly = false
+	}
+
+	type edge struct{ a, b int }
+	drawn := make(map[edge]bool)
+
+	for _, group := range mesh.Groups {
+		for _, tri := range group.Triangles {
+			for k := range 3 {
+				a, b := tri[k].Pos, tri[(k+1)%3].Pos
+				if a > b {
+					a, b = b, a
+				}
+				if drawn[edge{a, b}] {
+					continue
+				}
+				drawn[edge{a, b}] = true
+				r.DrawLine3D(mesh.Positions[a], mesh.Positions[b], color)
+			}
+		}
+	}
+}
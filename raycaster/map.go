@@ -0,0 +1,97 @@
+// Package raycaster renders Wolfenstein-style scenes into a quickcg screen
+// buffer, following the techniques of lodev's raycasting tutorials: DDA wall
+// casting with textured walls, floor and ceiling casting, and sprites hidden
+// behind walls with a per-column z-buffer.
+package raycaster
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// Map is a 2D grid of tiles. Tile 0 is empty space; any other value is a
+// wall drawn with texture (or color) number tile-1.
+type Map struct {
+	W, H  int
+	Tiles []int // row-major, indexed by y*W+x
+}
+
+// NewMap creates a map from rows of tiles. Row y of the slice is map row y,
+// and all rows must have the same length.
+func NewMap(rows [][]int) *Map {
+	m := &Map{H: len(rows)}
+	if m.H > 0 {
+		m.W = len(rows[0])
+	}
+
+	m.Tiles = make([]int, m.W*m.H)
+	for y, row := range rows {
+		copy(m.Tiles[y*m.W:(y+1)*m.W], row)
+	}
+	return m
+}
+
+// At returns the tile at (x, y). Cells outside the map count as empty.
+func (m *Map) At(x, y int) int {
+	if x < 0 || y < 0 || x >= m.W || y >= m.H {
+		return 0
+	}
+	return m.Tiles[y*m.W+x]
+}
+
+// Solid reports whether the point p lies inside a wall tile.
+func (m *Map) Solid(p vecmath.Vec2) bool {
+	return m.At(int(math.Floor(p.X)), int(math.Floor(p.Y))) != 0
+}
+
+// Camera is the viewer of a raycast scene. Dir is the viewing direction and
+// Plane the camera plane perpendicular to it, pointing to the right edge of
+// the screen; their length ratio sets the field of view. With the map drawn
+// y-down, Plane is Dir turned clockwise.
+type Camera struct {
+	Pos   vecmath.Vec2
+	Dir   vecmath.Vec2
+	Plane vecmath.Vec2
+}
+
+// NewCamera returns a camera at pos looking along dir with a horizontal
+// field of view of fov radians.
+func NewCamera(pos, dir vecmath.Vec2, fov float64) Camera {
+	dir = dir.Normalize()
+	return Camera{
+		Pos:   pos,
+		Dir:   dir,
+		Plane: vecmath.Vec2{X: -dir.Y, Y: dir.X}.Scale(math.Tan(fov / 2)),
+	}
+}
+
+// Rotate turns the camera by angle radians; positive angles turn right.
+func (cam *Camera) Rotate(angle float64) {
+	cam.Dir = cam.Dir.Rotate(angle)
+	cam.Plane = cam.Plane.Rotate(angle)
+}
+
+// Move walks dist units along the viewing direction, sliding along walls of m.
+// Collisions are only tested at the destination, so dist should stay well
+// below one tile, as it does when moving a little every frame.
+func (cam *Camera) Move(m *Map, dist float64) {
+	cam.slide(m, cam.Dir.Scale(dist))
+}
+
+// Strafe walks dist units sideways, to the right for positive dist,
+// sliding along walls of m.
+func (cam *Camera) Strafe(m *Map, dist float64) {
+	cam.slide(m, cam.Plane.Normalize().Scale(dist))
+}
+
+// slide moves the camera by d, one axis at a time, so that it can
+// glide along a wall instead of stopping at it.
+func (cam *Camera) slide(m *Map, d vecmath.Vec2) {
+	if !m.Solid(vecmath.Vec2{X: cam.Pos.X + d.X, Y: cam.Pos.Y}) {
+		cam.Pos.X += d.X
+	}
+	if !m.Solid(vecmath.Vec2{X: cam.Pos.X, Y: cam.Pos.Y + d.Y}) {
+		cam.Pos.Y += d.Y
+	}
+}
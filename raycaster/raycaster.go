@@ -0,0 +1,296 @@
+package raycaster
+
+import (
+	"math"
+	"slices"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// Sprite is a billboard standing in the scene, always facing the camera.
+type Sprite struct {
+	Pos     vecmath.Vec2
+	Texture *quickcg.Image
+}
+
+// minWallDist is the smallest wall distance used for projection.
+const minWallDist = 1e-4
+
+// Raycaster draws a Map as seen from a Camera.
+type Raycaster struct {
+	Map *Map
+
+	// Textures holds the wall textures; tile n uses Textures[n-1].
+	// Tiles without a texture are drawn with Colors[n-1] instead.
+	Textures []*quickcg.Image
+	Colors   []quickcg.ColorRGB
+
+	// Floor and Ceiling are tiled once per map cell. When nil, the
+	// solid FloorColor and CeilingColor are used.
+	Floor, Ceiling           *quickcg.Image
+	FloorColor, CeilingColor quickcg.ColorRGB
+
+	Sprites []Sprite
+	// SpriteKey is the sprite color treated as transparent.
+	SpriteKey quickcg.ColorRGB
+
+	// ShadeSides darkens walls facing north or south, which makes corners readable.
+	ShadeSides bool
+
+	zBuffer []float64
+	view    *quickcg.Image // render target of Render, sized to the clip rectangle
+}
+
+// New creates a raycaster for m with the given wall textures.
+func New(m *Map, textures []*quickcg.Image) *Raycaster {
+	return &Raycaster{
+		Map:          m,
+		Textures:     textures,
+		FloorColor:   quickcg.ColorRGB{R: 64, G: 64, B: 64},
+		CeilingColor: quickcg.ColorRGB{R: 32, G: 32, B: 32},
+		ShadeSides:   true,
+	}
+}
+
+// ZBuffer returns the perpendicular wall distance of every screen column
+// from the last render. The slice is reused by the next render.
+func (rc *Raycaster) ZBuffer() []float64 {
+	return rc.zBuffer
+}
+
+// Render draws the scene into the screen buffer, fitted into its clip
+// rectangle, so pushing a clip with PushClip draws an inset view. Call
+// DrawBuffer to show it.
+func (rc *Raycaster) Render(screen *quickcg.Screen, cam Camera) {
+	vp := screen.Clip()
+	if vp.Empty() {
+		return
+	}
+	if rc.view == nil || rc.view.W != vp.W || rc.view.H != vp.H {
+		rc.view = quickcg.NewImage(vp.W, vp.H)
+	}
+	rc.RenderImage(rc.view, cam)
+
+	ox, oy := screen.Origin()
+	screen.WriteImage(rc.view, vp.X-ox, vp.Y-oy)
+}
+
+// RenderImage draws the scene into img.
+func (rc *Raycaster) RenderImage(img *quickcg.Image, cam Camera) {
+	if len(rc.zBuffer) != img.W {
+		rc.zBuffer = make([]float64, img.W)
+	}
+
+	rc.castFloor(img, cam)
+	rc.castWalls(img, cam)
+	rc.drawSprites(img, cam)
+}
+
+// castFloor fills the lower half of img with the floor and the upper half
+// with the ceiling, one horizontal scanline at a time.
+func (rc *Raycaster) castFloor(img *quickcg.Image, cam Camera) {
+	w, h := img.W, img.H
+	rayDir0 := cam.Dir.Sub(cam.Plane)
+	rayDir1 := cam.Dir.Add(cam.Plane)
+	posZ := 0.5 * float64(h)
+
+	for y := h / 2; y < h; y++ {
+		ceilY := h - y - 1
+
+		p := float64(y) - float64(h)/2 + 0.5
+		rowDistance := posZ / p
+		step := rayDir1.Sub(rayDir0).Scale(rowDistance / float64(w))
+		floor := cam.Pos.Add(rayDir0.Scale(rowDistance))
+
+		for x := range w {
+			cellX, cellY := math.Floor(floor.X), math.Floor(floor.Y)
+			fx, fy := floor.X-cellX, floor.Y-cellY
+			floor = floor.Add(step)
+
+			img.Pixels[y*w+x] = sampleOr(rc.Floor, fx, fy, rc.FloorColor)
+			img.Pixels[ceilY*w+x] = sampleOr(rc.Ceiling, fx, fy, rc.CeilingColor)
+		}
+	}
+}
+
+// castWalls casts one ray per screen column through the map grid with a
+// digital differential analyzer and draws the textured wall slice it hits.
+func (rc *Raycaster) castWalls(img *quickcg.Image, cam Camera) {
+	w, h := img.W, img.H
+
+	for x := range w {
+		cameraX := 2*float64(x)/float64(w) - 1
+		rayDir := cam.Dir.Add(cam.Plane.Scale(cameraX))
+
+		mapX := int(math.Floor(cam.Pos.X))
+		mapY := int(math.Floor(cam.Pos.Y))
+		deltaX := math.Abs(1 / rayDir.X)
+		deltaY := math.Abs(1 / rayDir.Y)
+
+		stepX, stepY := 1, 1
+		sideX := (float64(mapX) + 1 - cam.Pos.X) * deltaX
+		sideY := (float64(mapY) + 1 - cam.Pos.Y) * deltaY
+		if rayDir.X < 0 {
+			stepX = -1
+			sideX = (cam.Pos.X - float64(mapX)) * deltaX
+		}
+		if rayDir.Y < 0 {
+			stepY = -1
+			sideY = (cam.Pos.Y - float64(mapY)) * deltaY
+		}
+
+		tile, side := 0, 0
+		for tile == 0 {
+			if sideX < sideY {
+				sideX += deltaX
+				mapX += stepX
+				side = 0
+			} else {
+				sideY += deltaY
+				mapY += stepY
+				side = 1
+			}
+			if mapX < 0 || mapY < 0 || mapX >= rc.Map.W || mapY >= rc.Map.H {
+				break
+			}
+			tile = rc.Map.At(mapX, mapY)
+		}
+
+		if tile == 0 {
+			rc.zBuffer[x] = math.Inf(1)
+			continue
+		}
+
+		dist := sideY - deltaY
+		if side == 0 {
+			dist = sideX - deltaX
+		}
+		// A camera exactly on a cell boundary is at distance 0 from the wall,
+		// which would make the wall infinitely tall.
+		dist = max(dist, minWallDist)
+		rc.zBuffer[x] = dist
+
+		lineHeight := int(float64(h) / dist)
+		drawStart := max(h/2-lineHeight/2, 0)
+		drawEnd := min(h/2+lineHeight/2, h-1)
+
+		// Where exactly the wall was hit, as a fraction of the tile side.
+		wallX := cam.Pos.X + dist*rayDir.X
+		if side == 0 {
+			wallX = cam.Pos.Y + dist*rayDir.Y
+		}
+		wallX -= math.Floor(wallX)
+		if (side == 0 && rayDir.X < 0) || (side == 1 && rayDir.Y > 0) {
+			wallX = 1 - wallX
+		}
+
+		tex := rc.texture(tile)
+		for y := drawStart; y <= drawEnd; y++ {
+			var color quickcg.ColorRGB
+			if tex != nil {
+				texY := (float64(y-h/2) + float64(lineHeight)/2) / float64(lineHeight)
+				color = sample(tex, wallX, texY)
+			} else {
+				color = rc.color(tile)
+			}
+			if rc.ShadeSides && side == 1 {
+				color = quickcg.ColorRGB{R: color.R / 2, G: color.G / 2, B: color.B / 2}
+			}
+			img.Pixels[y*w+x] = color
+		}
+	}
+}
+
+// drawSprites draws the sprites from far to near, column by column,
+// skipping columns where a wall is closer than the sprite.
+func (rc *Raycaster) drawSprites(img *quickcg.Image, cam Camera) {
+	w, h := img.W, img.H
+
+	order := make([]int, len(rc.Sprites))
+	dist := make([]float64, len(rc.Sprites))
+	for i, s := range rc.Sprites {
+		order[i] = i
+		dist[i] = s.Pos.Sub(cam.Pos).LenSq()
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case dist[a] > dist[b]:
+			return -1
+		case dist[a] < dist[b]:
+			return 1
+		}
+		return 0
+	})
+
+	invDet := 1 / (cam.Plane.X*cam.Dir.Y - cam.Dir.X*cam.Plane.Y)
+
+	for _, i := range order {
+		sprite := rc.Sprites[i]
+		if sprite.Texture == nil {
+			continue
+		}
+
+		// Transform the sprite into camera space; depth is its distance along Dir.
+		rel := sprite.Pos.Sub(cam.Pos)
+		tx := invDet * (cam.Dir.Y*rel.X - cam.Dir.X*rel.Y)
+		depth := invDet * (-cam.Plane.Y*rel.X + cam.Plane.X*rel.Y)
+		if depth <= 0 {
+			continue
+		}
+
+		screenX := float64(w) / 2 * (1 + tx/depth)
+		size := math.Abs(float64(h) / depth)
+		left := screenX - size/2
+		top := float64(h)/2 - size/2
+
+		startX := max(int(math.Floor(left)), 0)
+		endX := min(int(math.Ceil(left+size)), w)
+		startY := max(int(math.Floor(top)), 0)
+		endY := min(int(math.Ceil(top+size)), h)
+
+		for x := startX; x < endX; x++ {
+			if depth >= rc.zBuffer[x] {
+				continue
+			}
+			u := (float64(x) + 0.5 - left) / size
+			for y := startY; y < endY; y++ {
+				v := (float64(y) + 0.5 - top) / size
+				color := sample(sprite.Texture, u, v)
+				if color != rc.SpriteKey {
+					img.Pixels[y*w+x] = color
+				}
+			}
+		}
+	}
+}
+
+// texture returns the texture of a wall tile, or nil if it has none.
+func (rc *Raycaster) texture(tile int) *quickcg.Image {
+	if tile > 0 && tile-1 < len(rc.Textures) {
+		return rc.Textures[tile-1]
+	}
+	return nil
+}
+
+// color returns the solid color of a wall tile without a texture.
+func (rc *Raycaster) color(tile int) quickcg.ColorRGB {
+	if tile > 0 && tile-1 < len(rc.Colors) {
+		return rc.Colors[tile-1]
+	}
+	return quickcg.ColorRGB{R: 255, G: 255, B: 255}
+}
+
+// sample returns the texel at the fractional coordinates (u, v) in [0, 1).
+func sample(tex *quickcg.Image, u, v float64) quickcg.ColorRGB {
+	x := min(max(int(u*float64(tex.W)), 0), tex.W-1)
+	y := min(max(int(v*float64(tex.H)), 0), tex.H-1)
+	return tex.Pixels[y*tex.W+x]
+}
+
+func sampleOr(tex *quickcg.Image, u, v float64, color quickcg.ColorRGB) quickcg.ColorRGB {
+	if tex == nil {
+		return color
+	}
+	return sample(tex, u, v)
+}
@@ -0,0 +1,65 @@
+package raycaster
+
+import (
+	"math"
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+func TestRenderClip(t *testing.T) {
+	screen := quickcg.NewHeadlessScreen(80, 60)
+	buf := screen.BufferImage()
+	background := quickcg.ColorRGB{R: 1, G: 2, B: 3}
+	for i := range buf.Pixels {
+		buf.Pixels[i] = background
+	}
+
+	m := NewMap([][]int{
+		{1, 1, 1, 1},
+		{1, 0, 0, 1},
+		{1, 0, 0, 1},
+		{1, 1, 1, 1},
+	})
+	rc := New(m, nil)
+	rc.Colors = []quickcg.ColorRGB{{R: 200}}
+	cam := NewCamera(vecmath.Vec2{X: 2, Y: 2}, vecmath.Vec2{X: 1}, 0.66)
+
+	screen.PushTranslate(20, 10)
+	screen.PushClip(quickcg.Rect{W: 30, H: 20})
+	rc.Render(screen, cam)
+
+	inset := quickcg.Rect{X: 20, Y: 10, W: 30, H: 20}
+	for y := range buf.H {
+		for x := range buf.W {
+			c := buf.Pixels[y*buf.W+x]
+			if inside := inset.Contains(x, y); inside == (c == background) {
+				t.Fatalf("pixel (%d, %d) inside=%v has color %v", x, y, inside, c)
+			}
+		}
+	}
+	if len(rc.ZBuffer()) != inset.W {
+		t.Errorf("len(ZBuffer()) = %d, want %d", len(rc.ZBuffer()), inset.W)
+	}
+}
+
+func TestRenderOnCellBoundary(t *testing.T) {
+	m := NewMap([][]int{
+		{1, 1, 1},
+		{1, 0, 1},
+		{1, 1, 1},
+	})
+	rc := New(m, []*quickcg.Image{quickcg.NewImage(4, 4)})
+	img := quickcg.NewImage(40, 30)
+
+	// The camera touches the wall to its left.
+	cam := NewCamera(vecmath.Vec2{X: 1, Y: 1.5}, vecmath.Vec2{X: -1}, 0.66)
+	rc.RenderImage(img, cam)
+
+	for x, d := range rc.ZBuffer() {
+		if d <= 0 || math.IsInf(d, 0) || math.IsNaN(d) {
+			t.Errorf("ZBuffer()[%d] = %v, want a small positive distance", x, d)
+		}
+	}
+}
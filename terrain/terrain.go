@@ -0,0 +1,187 @@
+// Package terrain renders heightmap landscapes in the style of the Comanche
+// voxel engine: the map is drawn front to back as screen columns, a y-buffer
+// hides whatever is behind nearer hills, and the sampling distance grows with
+// depth for a cheap level of detail.
+package terrain
+
+import (
+	"math"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+// Camera is the viewer of a terrain, in map pixel coordinates.
+type Camera struct {
+	Pos      vecmath.Vec2 // position on the map
+	Height   float64      // altitude, in the units of the height map (0-255)
+	Yaw      float64      // heading in radians; 0 looks toward -y, positive turns right
+	Horizon  float64      // row of the horizon in the view; change it to look up or down
+	Distance float64      // how far to draw, in map pixels
+	FOV      float64      // horizontal field of view in radians
+}
+
+// NewCamera returns a camera at pos and height with a 90° field of view,
+// the horizon at view row horizon and a view distance of 800 map pixels.
+func NewCamera(pos vecmath.Vec2, height, horizon float64) Camera {
+	return Camera{
+		Pos:      pos,
+		Height:   height,
+		Horizon:  horizon,
+		Distance: 800,
+		FOV:      math.Pi / 2,
+	}
+}
+
+// Dir returns the unit viewing direction on the map.
+func (cam Camera) Dir() vecmath.Vec2 {
+	s, c := math.Sincos(cam.Yaw)
+	return vecmath.Vec2{X: s, Y: -c}
+}
+
+// Renderer draws a terrain given by a color map and a height map of the same
+// size. Both maps wrap around at their edges.
+type Renderer struct {
+	ColorMap  *quickcg.Image
+	HeightMap *quickcg.Image // the red channel is the height
+
+	// Scale converts heights to screen pixels at a distance of one map pixel.
+	Scale float64
+	// LOD is added to the sampling step after every row, so far rows are
+	// sampled more coarsely. Zero samples every map pixel.
+	LOD float64
+
+	Sky quickcg.ColorRGB
+
+	// Fog blends rows between FogStart and FogEnd toward FogColor.
+	// Fog is disabled while FogEnd is zero.
+	FogColor         quickcg.ColorRGB
+	FogStart, FogEnd float64
+
+	yBuffer []float64
+	view    *quickcg.Image // render target of Render, sized to the clip rectangle
+}
+
+// New creates a renderer for the given color and height maps.
+func New(colorMap, heightMap *quickcg.Image) *Renderer {
+	return &Renderer{
+		ColorMap:  colorMap,
+		HeightMap: heightMap,
+		Scale:     240,
+		LOD:       0.01,
+		Sky:       quickcg.ColorRGB{R: 150, G: 190, B: 230},
+	}
+}
+
+// Load reads the color and height maps from PNG files and creates a renderer.
+func Load(colorPath, heightPath string) (*Renderer, error) {
+	colorMap, err := quickcg.LoadImage(colorPath)
+	if err != nil {
+		return nil, err
+	}
+	heightMap, err := quickcg.LoadImage(heightPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(colorMap, heightMap), nil
+}
+
+// HeightAt returns the terrain height at map position (x, y), which is
+// handy to keep the camera above the ground.
+func (r *Renderer) HeightAt(x, y float64) float64 {
+	return float64(wrapAt(r.HeightMap, x, y).R)
+}
+
+// Render draws the terrain into the screen buffer, fitted into its clip
+// rectangle, so pushing a clip with PushClip draws an inset view. Call
+// DrawBuffer to show it.
+func (r *Renderer) Render(screen *quickcg.Screen, cam Camera) {
+	vp := screen.Clip()
+	if vp.Empty() {
+		return
+	}
+	if r.view == nil || r.view.W != vp.W || r.view.H != vp.H {
+		r.view = quickcg.NewImage(vp.W, vp.H)
+	}
+	r.RenderImage(r.view, cam)
+
+	ox, oy := screen.Origin()
+	screen.WriteImage(r.view, vp.X-ox, vp.Y-oy)
+}
+
+// RenderImage draws the terrain into img.
+func (r *Renderer) RenderImage(img *quickcg.Image, cam Camera) {
+	w, h := img.W, img.H
+	for i := range img.Pixels {
+		img.Pixels[i] = r.Sky
+	}
+
+	if len(r.yBuffer) != w {
+		r.yBuffer = make([]float64, w)
+	}
+	for i := range r.yBuffer {
+		r.yBuffer[i] = float64(h)
+	}
+
+	dir := cam.Dir()
+	right := vecmath.Vec2{X: -dir.Y, Y: dir.X}.Scale(math.Tan(cam.FOV / 2))
+
+	// Walk rows of the map from near to far. Each row is a line across
+	// the view frustum, sampled once per screen column.
+	for z, dz := 1.0, 1.0; z < cam.Distance; z, dz = z+dz, dz+r.LOD {
+		left := cam.Pos.Add(dir.Sub(right).Scale(z))
+		step := right.Scale(2 * z / float64(w))
+		fog := r.fog(z)
+
+		p := left
+		for x := range w {
+			height := float64(wrapAt(r.HeightMap, p.X, p.Y).R)
+			top := (cam.Height-height)/z*r.Scale + cam.Horizon
+
+			if top < r.yBuffer[x] {
+				color := wrapAt(r.ColorMap, p.X, p.Y)
+				if fog > 0 {
+					color = lerpColor(color, r.FogColor, fog)
+				}
+				for y := max(int(math.Ceil(top)), 0); y < int(math.Ceil(r.yBuffer[x])); y++ {
+					img.Pixels[y*w+x] = color
+				}
+				r.yBuffer[x] = top
+			}
+			p = p.Add(step)
+		}
+	}
+}
+
+// fog returns how much of the fog color to blend in at distance z.
+func (r *Renderer) fog(z float64) float64 {
+	if r.FogEnd <= 0 || z <= r.FogStart {
+		return 0
+	}
+	if z >= r.FogEnd {
+		return 1
+	}
+	return (z - r.FogStart) / (r.FogEnd - r.FogStart)
+}
+
+// wrapAt returns the pixel of img at (x, y), repeating the image in both directions.
+func wrapAt(img *quickcg.Image, x, y float64) quickcg.ColorRGB {
+	ix := int(math.Floor(x)) % img.W
+	iy := int(math.Floor(y)) % img.H
+	if ix < 0 {
+		ix += img.W
+	}
+	if iy < 0 {
+		iy += img.H
+	}
+	return img.Pixels[iy*img.W+ix]
+}
+
+func lerpColor(a, b quickcg.ColorRGB, t float64) quickcg.ColorRGB {
+	return quickcg.ColorRGB{
+		R: uint8(float64(a.R) + (float64(b.R)-float64(a.R))*t + 0.5),
+		G: uint8(float64(a.G) + (float64(b.G)-float64(a.G))*t + 0.5),
+		B: uint8(float64(a.B) + (float64(b.B)-float64(a.B))*t + 0.5),
+	}
+}
@@ -0,0 +1,40 @@
+package terrain
+
+import (
+	"testing"
+
+	"github.com/RostislavArts/quickcgo/quickcg"
+	"github.com/RostislavArts/quickcgo/vecmath"
+)
+
+func TestRenderClip(t *testing.T) {
+	screen := quickcg.NewHeadlessScreen(80, 60)
+	buf := screen.BufferImage()
+	background := quickcg.ColorRGB{R: 1, G: 2, B: 3}
+	for i := range buf.Pixels {
+		buf.Pixels[i] = background
+	}
+
+	colorMap := quickcg.NewImage(16, 16)
+	heightMap := quickcg.NewImage(16, 16)
+	for i := range colorMap.Pixels {
+		colorMap.Pixels[i] = quickcg.ColorRGB{G: 200}
+		heightMap.Pixels[i] = quickcg.ColorRGB{R: 50}
+	}
+	r := New(colorMap, heightMap)
+	cam := NewCamera(vecmath.Vec2{X: 8, Y: 8}, 100, 10)
+
+	screen.PushTranslate(20, 10)
+	screen.PushClip(quickcg.Rect{W: 30, H: 20})
+	r.Render(screen, cam)
+
+	inset := quickcg.Rect{X: 20, Y: 10, W: 30, H: 20}
+	for y := range buf.H {
+		for x := range buf.W {
+			c := buf.Pixels[y*buf.W+x]
+			if inside := inset.Contains(x, y); inside == (c == background) {
+				t.Fatalf("pixel (%d, %d) inside=%v has color %v", x, y, inside, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package vecmath
+
+import (
+	"math"
+)
+
+// Mat3 is a 3x3 matrix stored row-major: element (row, col) is m[row*3+col].
+// It represents linear 3D transforms or homogeneous 2D transforms.
+type Mat3 [9]float64
+
+// Ident3 returns the 3x3 identity matrix.
+func Ident3() Mat3 {
+	return Mat3{
+		1, 0, 0,
+		0, 1, 0,
+		0, 0, 1,
+	}
+}
+
+// Translate2D returns a homogeneous 2D translation by (tx, ty).
+func Translate2D(tx, ty float64) Mat3 {
+	return Mat3{
+		1, 0, tx,
+		0, 1, ty,
+		0, 0, 1,
+	}
+}
+
+// Rotate2D returns a homogeneous 2D counter-clockwise rotation by angle radians.
+func Rotate2D(angle float64) Mat3 {
+	s, c := math.Sincos(angle)
+	return Mat3{
+		c, -s, 0,
+		s, c, 0,
+		0, 0, 1,
+	}
+}
+
+// Scale2D returns a homogeneous 2D scale by (sx, sy).
+func Scale2D(sx, sy float64) Mat3 {
+	return Mat3{
+		sx, 0, 0,
+		0, sy, 0,
+		0, 0, 1,
+	}
+}
+
+// Homography2D returns the homogeneous 2D projective transform that maps the
+// four points from onto the four points to, for example the corners of a
+// rectangle onto those of a quadrilateral. The second result is false if
+// three of the points in either set lie on a line.
+func Homography2D(from, to [4]Vec2) (Mat3, bool) {
+	if !generalPosition(from) || !generalPosition(to) {
+		return Ident3(), false
+	}
+
+	// Each correspondence gives two linear equations in the first eight
+	// matrix elements, with the last one fixed to 1.
+	var a [8][9]float64
+	for i := range 4 {
+		x, y := from[i].X, from[i].Y
+		u, v := to[i].X, to[i].Y
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -u * x, -u * y, u}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -v * x, -v * y, v}
+	}
+
+	// Gauss-Jordan elimination with partial pivoting.
+	for col := range 8 {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return Ident3(), false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+
+		for row := range 8 {
+			if row == col {
+				continue
+			}
+			f := a[row][col] / a[col][col]
+			for k := col; k < 9; k++ {
+				a[row][k] -= f * a[col][k]
+			}
+		}
+	}
+
+	var m Mat3
+	for i := range 8 {
+		m[i] = a[i][8] / a[i][i]
+	}
+	m[8] = 1
+	return m, true
+}
+
+// generalPosition reports whether no three of the points lie on a line.
+func generalPosition(p [4]Vec2) bool {
+	size := 0.0
+	for i := 1; i < 4; i++ {
+		size = math.Max(size, p[i].Sub(p[0]).LenSq())
+	}
+	for skip := range 4 {
+		var q []Vec2
+		for i := range 4 {
+			if i != skip {
+				q = append(q, p[i])
+			}
+		}
+		if math.Abs(q[1].Sub(q[0]).Cross(q[2].Sub(q[0]))) <= 1e-12*size {
+			return false
+		}
+	}
+	return true
+}
+
+// At returns the element in the given row and column.
+func (m Mat3) At(row, col int) float64 { return m[row*3+col] }
+
+// Mul returns the matrix product m * n.
+func (m Mat3) Mul(n Mat3) Mat3 {
+	var r Mat3
+	for i := range 3 {
+		for j := range 3 {
+			r[i*3+j] = m[i*3]*n[j] + m[i*3+1]*n[3+j] + m[i*3+2]*n[6+j]
+		}
+	}
+	return r
+}
+
+// MulVec3 returns m * v.
+func (m Mat3) MulVec3(v Vec3) Vec3 {
+	return Vec3{
+		m[0]*v.X + m[1]*v.Y + m[2]*v.Z,
+		m[3]*v.X + m[4]*v.Y + m[5]*v.Z,
+		m[6]*v.X + m[7]*v.Y + m[8]*v.Z,
+	}
+}
+
+// MulPoint2 transforms the 2D point p by the homogeneous matrix m.
+func (m Mat3) MulPoint2(p Vec2) Vec2 {
+	v := m.MulVec3(Vec3{p.X, p.Y, 1})
+	if v.Z != 0 && v.Z != 1 {
+		return Vec2{v.X / v.Z, v.Y / v.Z}
+	}
+	return Vec2{v.X, v.Y}
+}
+
+// MulDir2 transforms the 2D direction d by m, ignoring translation.
+func (m Mat3) MulDir2(d Vec2) Vec2 {
+	return Vec2{m[0]*d.X + m[1]*d.Y, m[3]*d.X + m[4]*d.Y}
+}
+
+// Transpose returns the transpose of m.
+func (m Mat3) Transpose() Mat3 {
+	return Mat3{
+		m[0], m[3], m[6],
+		m[1], m[4], m[7],
+		m[2], m[5], m[8],
+	}
+}
+
+// Det returns the determinant of m.
+func (m Mat3) Det() float64 {
+	return m[0]*(m[4]*m[8]-m[5]*m[7]) -
+		m[1]*(m[3]*m[8]-m[5]*m[6]) +
+		m[2]*(m[3]*m[7]-m[4]*m[6])
+}
+
+// Inverse returns the inverse of m. The second result is false
+// if m is singular, in which case the identity is returned.
+func (m Mat3) Inverse() (Mat3, bool) {
+	det := m.Det()
+	if det == 0 {
+		return Ident3(), false
+	}
+
+	inv := 1 / det
+	return Mat3{
+		(m[4]*m[8] - m[5]*m[7]) * inv,
+		(m[2]*m[7] - m[1]*m[8]) * inv,
+		(m[1]*m[5] - m[2]*m[4]) * inv,
+		(m[5]*m[6] - m[3]*m[8]) * inv,
+		(m[0]*m[8] - m[2]*m[6]) * inv,
+		(m[2]*m[3] - m[0]*m[5]) * inv,
+		(m[3]*m[7] - m[4]*m[6]) * inv,
+		(m[1]*m[6] - m[0]*m[7]) * inv,
+		(m[0]*m[4] - m[1]*m[3]) * inv,
+	}, true
+}
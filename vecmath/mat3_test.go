@@ -0,0 +1,76 @@
+package vecmath
+
+import (
+	"testing"
+)
+
+func nearMat3(a, b Mat3) bool {
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func nearVec2(a, b Vec2) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y)
+}
+
+func TestMat3Inverse(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Mat3
+	}{
+		{"identity", Ident3()},
+		{"translate", Translate2D(3, -4)},
+		{"rotate", Rotate2D(0.9)},
+		{"scale", Scale2D(2, -0.5)},
+		{"composite", Translate2D(1, 2).Mul(Rotate2D(-2.1)).Mul(Scale2D(3, 4))},
+		{"projective", Mat3{1, 2, 3, 0, 1, 4, 5, 6, 0}},
+	}
+	for _, tt := range tests {
+		inv, ok := tt.m.Inverse()
+		if !ok {
+			t.Errorf("%s: not invertible", tt.name)
+			continue
+		}
+		if got := tt.m.Mul(inv); !nearMat3(got, Ident3()) {
+			t.Errorf("%s: M * M⁻¹ = %v, want identity", tt.name, got)
+		}
+	}
+
+	if _, ok := (Mat3{1, 2, 3, 2, 4, 6, 0, 0, 1}).Inverse(); ok {
+		t.Error("singular matrix reported as invertible")
+	}
+}
+
+func TestHomography2D(t *testing.T) {
+	square := [4]Vec2{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	tests := []struct {
+		name string
+		to   [4]Vec2
+	}{
+		{"identity", square},
+		{"affine", [4]Vec2{{2, 1}, {4, 1}, {4, 5}, {2, 5}}},
+		{"projective", [4]Vec2{{10, 10}, {90, 20}, {70, 80}, {20, 60}}},
+	}
+	for _, tt := range tests {
+		h, ok := Homography2D(square, tt.to)
+		if !ok {
+			t.Errorf("%s: no homography found", tt.name)
+			continue
+		}
+		for i, p := range square {
+			if got := h.MulPoint2(p); !nearVec2(got, tt.to[i]) {
+				t.Errorf("%s: corner %v maps to %v, want %v", tt.name, p, got, tt.to[i])
+			}
+		}
+	}
+
+	// Three collinear points give no transform.
+	line := [4]Vec2{{0, 0}, {1, 1}, {2, 2}, {0, 1}}
+	if _, ok := Homography2D(square, line); ok {
+		t.Error("collinear target points accepted")
+	}
+}
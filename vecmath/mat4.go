@@ -0,0 +1,236 @@
+package vecmath
+
+import (
+	"math"
+)
+
+// Mat4 is a 4x4 matrix stored row-major: element (row, col) is m[row*4+col].
+type Mat4 [16]float64
+
+// Ident4 returns the 4x4 identity matrix.
+func Ident4() Mat4 {
+	return Mat4{
+		1, 0, 0, 0,
+		0, 1, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Translate returns a translation by t.
+func Translate(t Vec3) Mat4 {
+	return Mat4{
+		1, 0, 0, t.X,
+		0, 1, 0, t.Y,
+		0, 0, 1, t.Z,
+		0, 0, 0, 1,
+	}
+}
+
+// Scale returns a scale by s along each axis.
+func Scale(s Vec3) Mat4 {
+	return Mat4{
+		s.X, 0, 0, 0,
+		0, s.Y, 0, 0,
+		0, 0, s.Z, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// RotateX returns a rotation by angle radians around the X axis.
+func RotateX(angle float64) Mat4 {
+	s, c := math.Sincos(angle)
+	return Mat4{
+		1, 0, 0, 0,
+		0, c, -s, 0,
+		0, s, c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// RotateY returns a rotation by angle radians around the Y axis.
+func RotateY(angle float64) Mat4 {
+	s, c := math.Sincos(angle)
+	return Mat4{
+		c, 0, s, 0,
+		0, 1, 0, 0,
+		-s, 0, c, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// RotateZ returns a rotation by angle radians around the Z axis.
+func RotateZ(angle float64) Mat4 {
+	s, c := math.Sincos(angle)
+	return Mat4{
+		c, -s, 0, 0,
+		s, c, 0, 0,
+		0, 0, 1, 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Rotate returns a rotation by angle radians around the given axis.
+func Rotate(axis Vec3, angle float64) Mat4 {
+	return QuatFromAxisAngle(axis, angle).Mat4()
+}
+
+// LookAt returns a view matrix for a camera at eye looking at target,
+// with up giving the approximate upward direction.
+func LookAt(eye, target, up Vec3) Mat4 {
+	f := target.Sub(eye).Normalize()
+	s := f.Cross(up).Normalize()
+	u := s.Cross(f)
+
+	return Mat4{
+		s.X, s.Y, s.Z, -s.Dot(eye),
+		u.X, u.Y, u.Z, -u.Dot(eye),
+		-f.X, -f.Y, -f.Z, f.Dot(eye),
+		0, 0, 0, 1,
+	}
+}
+
+// Perspective returns a perspective projection with a vertical field of view
+// of fovy radians, the given width/height aspect ratio and clip planes.
+func Perspective(fovy, aspect, near, far float64) Mat4 {
+	f := 1 / math.Tan(fovy/2)
+	nf := 1 / (near - far)
+
+	return Mat4{
+		f / aspect, 0, 0, 0,
+		0, f, 0, 0,
+		0, 0, (far + near) * nf, 2 * far * near * nf,
+		0, 0, -1, 0,
+	}
+}
+
+// Orthographic returns an orthographic projection of the given view volume.
+func Orthographic(left, right, bottom, top, near, far float64) Mat4 {
+	rl := 1 / (right - left)
+	tb := 1 / (top - bottom)
+	fn := 1 / (far - near)
+
+	return Mat4{
+		2 * rl, 0, 0, -(right + left) * rl,
+		0, 2 * tb, 0, -(top + bottom) * tb,
+		0, 0, -2 * fn, -(far + near) * fn,
+		0, 0, 0, 1,
+	}
+}
+
+// Viewport returns the transform from normalized device coordinates to
+// pixel coordinates of a w x h screen. Y is flipped so that it grows
+// downwards, as in quickcg, and depth is mapped from [-1, 1] to [0, 1].
+func Viewport(w, h int) Mat4 {
+	hw, hh := float64(w)/2, float64(h)/2
+	return Mat4{
+		hw, 0, 0, hw,
+		0, -hh, 0, hh,
+		0, 0, 0.5, 0.5,
+		0, 0, 0, 1,
+	}
+}
+
+// At returns the element in the given row and column.
+func (m Mat4) At(row, col int) float64 { return m[row*4+col] }
+
+// Mul returns the matrix product m * n.
+func (m Mat4) Mul(n Mat4) Mat4 {
+	var r Mat4
+	for i := range 4 {
+		for j := range 4 {
+			r[i*4+j] = m[i*4]*n[j] + m[i*4+1]*n[4+j] + m[i*4+2]*n[8+j] + m[i*4+3]*n[12+j]
+		}
+	}
+	return r
+}
+
+// MulVec4 returns m * v.
+func (m Mat4) MulVec4(v Vec4) Vec4 {
+	return Vec4{
+		m[0]*v.X + m[1]*v.Y + m[2]*v.Z + m[3]*v.W,
+		m[4]*v.X + m[5]*v.Y + m[6]*v.Z + m[7]*v.W,
+		m[8]*v.X + m[9]*v.Y + m[10]*v.Z + m[11]*v.W,
+		m[12]*v.X + m[13]*v.Y + m[14]*v.Z + m[15]*v.W,
+	}
+}
+
+// MulPoint transforms the point p by m, including the perspective divide.
+func (m Mat4) MulPoint(p Vec3) Vec3 {
+	v := m.MulVec4(p.Vec4(1))
+	if v.W != 0 && v.W != 1 {
+		return v.PerspectiveDivide()
+	}
+	return v.Vec3()
+}
+
+// MulDir transforms the direction d by m, ignoring translation.
+func (m Mat4) MulDir(d Vec3) Vec3 {
+	return m.MulVec4(d.Vec4(0)).Vec3()
+}
+
+// Mat3 returns the upper-left 3x3 part of m.
+func (m Mat4) Mat3() Mat3 {
+	return Mat3{
+		m[0], m[1], m[2],
+		m[4], m[5], m[6],
+		m[8], m[9], m[10],
+	}
+}
+
+// Transpose returns the transpose of m.
+func (m Mat4) Transpose() Mat4 {
+	var r Mat4
+	for i := range 4 {
+		for j := range 4 {
+			r[j*4+i] = m[i*4+j]
+		}
+	}
+	return r
+}
+
+// Det returns the determinant of m.
+func (m Mat4) Det() float64 {
+	c := m.cofactors()
+	return m[0]*c[0] + m[1]*c[4] + m[2]*c[8] + m[3]*c[12]
+}
+
+// Inverse returns the inverse of m. The second result is false
+// if m is singular, in which case the identity is returned.
+func (m Mat4) Inverse() (Mat4, bool) {
+	c := m.cofactors()
+	det := m[0]*c[0] + m[1]*c[4] + m[2]*c[8] + m[3]*c[12]
+	if det == 0 {
+		return Ident4(), false
+	}
+
+	inv := 1 / det
+	for i := range c {
+		c[i] *= inv
+	}
+	return c, true
+}
+
+// cofactors returns the adjugate of m (the transposed cofactor matrix).
+func (m Mat4) cofactors() Mat4 {
+	var r Mat4
+
+	r[0] = m[5]*m[10]*m[15] - m[5]*m[11]*m[14] - m[9]*m[6]*m[15] + m[9]*m[7]*m[14] + m[13]*m[6]*m[11] - m[13]*m[7]*m[10]
+	r[4] = -m[4]*m[10]*m[15] + m[4]*m[11]*m[14] + m[8]*m[6]*m[15] - m[8]*m[7]*m[14] - m[12]*m[6]*m[11] + m[12]*m[7]*m[10]
+	r[8] = m[4]*m[9]*m[15] - m[4]*m[11]*m[13] - m[8]*m[5]*m[15] + m[8]*m[7]*m[13] + m[12]*m[5]*m[11] - m[12]*m[7]*m[9]
+	r[12] = -m[4]*m[9]*m[14] + m[4]*m[10]*m[13] + m[8]*m[5]*m[14] - m[8]*m[6]*m[13] - m[12]*m[5]*m[10] + m[12]*m[6]*m[9]
+	r[1] = -m[1]*m[10]*m[15] + m[1]*m[11]*m[14] + m[9]*m[2]*m[15] - m[9]*m[3]*m[14] - m[13]*m[2]*m[11] + m[13]*m[3]*m[10]
+	r[5] = m[0]*m[10]*m[15] - m[0]*m[11]*m[14] - m[8]*m[2]*m[15] + m[8]*m[3]*m[14] + m[12]*m[2]*m[11] - m[12]*m[3]*m[10]
+	r[9] = -m[0]*m[9]*m[15] + m[0]*m[11]*m[13] + m[8]*m[1]*m[15] - m[8]*m[3]*m[13] - m[12]*m[1]*m[11] + m[12]*m[3]*m[9]
+	r[13] = m[0]*m[9]*m[14] - m[0]*m[10]*m[13] - m[8]*m[1]*m[14] + m[8]*m[2]*m[13] + m[12]*m[1]*m[10] - m[12]*m[2]*m[9]
+	r[2] = m[1]*m[6]*m[15] - m[1]*m[7]*m[14] - m[5]*m[2]*m[15] + m[5]*m[3]*m[14] + m[13]*m[2]*m[7] - m[13]*m[3]*m[6]
+	r[6] = -m[0]*m[6]*m[15] + m[0]*m[7]*m[14] + m[4]*m[2]*m[15] - m[4]*m[3]*m[14] - m[12]*m[2]*m[7] + m[12]*m[3]*m[6]
+	r[10] = m[0]*m[5]*m[15] - m[0]*m[7]*m[13] - m[4]*m[1]*m[15] + m[4]*m[3]*m[13] + m[12]*m[1]*m[7] - m[12]*m[3]*m[5]
+	r[14] = -m[0]*m[5]*m[14] + m[0]*m[6]*m[13] + m[4]*m[1]*m[14] - m[4]*m[2]*m[13] - m[12]*m[1]*m[6] + m[12]*m[2]*m[5]
+	r[3] = -m[1]*m[6]*m[11] + m[1]*m[7]*m[10] + m[5]*m[2]*m[11] - m[5]*m[3]*m[10] - m[9]*m[2]*m[7] + m[9]*m[3]*m[6]
+	r[7] = m[0]*m[6]*m[11] - m[0]*m[7]*m[10] - m[4]*m[2]*m[11] + m[4]*m[3]*m[10] + m[8]*m[2]*m[7] - m[8]*m[3]*m[6]
+	r[11] = -m[0]*m[5]*m[11] + m[0]*m[7]*m[9] + m[4]*m[1]*m[11] - m[4]*m[3]*m[9] - m[8]*m[1]*m[7] + m[8]*m[3]*m[5]
+	r[15] = m[0]*m[5]*m[10] - m[0]*m[6]*m[9] - m[4]*m[1]*m[10] + m[4]*m[2]*m[9] + m[8]*m[1]*m[6] - m[8]*m[2]*m[5]
+
+	return r
+}
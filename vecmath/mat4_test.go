@@ -0,0 +1,99 @@
+package vecmath
+
+import (
+	"math"
+	"testing"
+)
+
+const eps = 1e-9
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= eps*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+}
+
+func nearMat4(a, b Mat4) bool {
+	for i := range a {
+		if !near(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func nearVec3(a, b Vec3) bool {
+	return near(a.X, b.X) && near(a.Y, b.Y) && near(a.Z, b.Z)
+}
+
+func TestMat4Inverse(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Mat4
+	}{
+		{"identity", Ident4()},
+		{"translate", Translate(Vec3{1, -2, 3})},
+		{"scale", Scale(Vec3{2, 0.5, -4})},
+		{"rotate", Rotate(Vec3{1, 2, 3}, 0.7)},
+		{"composite", Translate(Vec3{4, 5, 6}).Mul(RotateY(1.2)).Mul(Scale(Vec3{1, 2, 3}))},
+		{"look at", LookAt(Vec3{3, 2, 5}, Vec3{}, Vec3{Y: 1})},
+		{"perspective", Perspective(math.Pi/3, 1.5, 0.1, 100)},
+		{"orthographic", Orthographic(-2, 3, -1, 4, 0.5, 20)},
+	}
+	for _, tt := range tests {
+		inv, ok := tt.m.Inverse()
+		if !ok {
+			t.Errorf("%s: not invertible", tt.name)
+			continue
+		}
+		if got := tt.m.Mul(inv); !nearMat4(got, Ident4()) {
+			t.Errorf("%s: M * M⁻¹ = %v, want identity", tt.name, got)
+		}
+		if got := inv.Mul(tt.m); !nearMat4(got, Ident4()) {
+			t.Errorf("%s: M⁻¹ * M = %v, want identity", tt.name, got)
+		}
+	}
+
+	if _, ok := Scale(Vec3{1, 0, 1}).Inverse(); ok {
+		t.Error("singular matrix reported as invertible")
+	}
+}
+
+func TestLookAt(t *testing.T) {
+	eye := Vec3{3, 2, 5}
+	target := Vec3{1, 1, -1}
+	view := LookAt(eye, target, Vec3{Y: 1})
+
+	if got := view.MulPoint(eye); !nearVec3(got, Vec3{}) {
+		t.Errorf("eye maps to %v, want the origin", got)
+	}
+	// The target lies straight ahead, down -Z.
+	want := Vec3{Z: -target.Sub(eye).Len()}
+	if got := view.MulPoint(target); !nearVec3(got, want) {
+		t.Errorf("target maps to %v, want %v", got, want)
+	}
+	// A point above the eye stays in the upper half of the view.
+	if got := view.MulPoint(eye.Add(Vec3{Y: 1})); got.Y <= 0 || !near(got.X, 0) {
+		t.Errorf("point above the eye maps to %v", got)
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	near_, far := 0.5, 50.0
+	p := Perspective(math.Pi/2, 2, near_, far)
+
+	tests := []struct {
+		eye  Vec3
+		want Vec3 // normalized device coordinates
+	}{
+		{Vec3{Z: -near_}, Vec3{Z: -1}},
+		{Vec3{Z: -far}, Vec3{Z: 1}},
+		// With a 90° field of view the top edge is at y = -z.
+		{Vec3{Y: 3, Z: -3}, Vec3{Y: 1, Z: p.MulPoint(Vec3{Z: -3}).Z}},
+		// The right edge is wider by the aspect ratio.
+		{Vec3{X: 6, Z: -3}, Vec3{X: 1, Z: p.MulPoint(Vec3{Z: -3}).Z}},
+	}
+	for _, tt := range tests {
+		if got := p.MulVec4(tt.eye.Vec4(1)).PerspectiveDivide(); !nearVec3(got, tt.want) {
+			t.Errorf("%v projects to %v, want %v", tt.eye, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package vecmath
+
+import (
+	"math"
+)
+
+// Quat is a quaternion W + Xi + Yj + Zk. Unit quaternions represent rotations.
+type Quat struct {
+	W, X, Y, Z float64
+}
+
+// QuatIdent returns the identity rotation.
+func QuatIdent() Quat {
+	return Quat{W: 1}
+}
+
+// QuatFromAxisAngle returns the rotation by angle radians around axis.
+func QuatFromAxisAngle(axis Vec3, angle float64) Quat {
+	a := axis.Normalize()
+	s, c := math.Sincos(angle / 2)
+	return Quat{W: c, X: a.X * s, Y: a.Y * s, Z: a.Z * s}
+}
+
+// QuatFromEuler returns the rotation that applies x, y and z radians
+// around the X, Y and Z axes, in that order.
+func QuatFromEuler(x, y, z float64) Quat {
+	qx := QuatFromAxisAngle(Vec3{1, 0, 0}, x)
+	qy := QuatFromAxisAngle(Vec3{0, 1, 0}, y)
+	qz := QuatFromAxisAngle(Vec3{0, 0, 1}, z)
+	return qz.Mul(qy).Mul(qx)
+}
+
+// Mul returns the Hamilton product q * r, the rotation r followed by q.
+func (q Quat) Mul(r Quat) Quat {
+	return Quat{
+		W: q.W*r.W - q.X*r.X - q.Y*r.Y - q.Z*r.Z,
+		X: q.W*r.X + q.X*r.W + q.Y*r.Z - q.Z*r.Y,
+		Y: q.W*r.Y - q.X*r.Z + q.Y*r.W + q.Z*r.X,
+		Z: q.W*r.Z + q.X*r.Y - q.Y*r.X + q.Z*r.W,
+	}
+}
+
+// Dot returns the dot product of q and r.
+func (q Quat) Dot(r Quat) float64 {
+	return q.W*r.W + q.X*r.X + q.Y*r.Y + q.Z*r.Z
+}
+
+// Len returns the norm of q.
+func (q Quat) Len() float64 { return math.Sqrt(q.Dot(q)) }
+
+// Normalize returns q scaled to unit length, or the identity if q is zero.
+func (q Quat) Normalize() Quat {
+	l := q.Len()
+	if l == 0 {
+		return QuatIdent()
+	}
+	return Quat{q.W / l, q.X / l, q.Y / l, q.Z / l}
+}
+
+// Conjugate returns the conjugate of q, the inverse rotation for unit quaternions.
+func (q Quat) Conjugate() Quat {
+	return Quat{q.W, -q.X, -q.Y, -q.Z}
+}
+
+// Inverse returns the multiplicative inverse of q.
+func (q Quat) Inverse() Quat {
+	d := q.Dot(q)
+	if d == 0 {
+		return QuatIdent()
+	}
+	c := q.Conjugate()
+	return Quat{c.W / d, c.X / d, c.Y / d, c.Z / d}
+}
+
+// Rotate rotates v by the unit quaternion q.
+func (q Quat) Rotate(v Vec3) Vec3 {
+	u := Vec3{q.X, q.Y, q.Z}
+	t := u.Cross(v).Scale(2)
+	return v.Add(t.Scale(q.W)).Add(u.Cross(t))
+}
+
+// Mat4 returns the rotation matrix of the unit quaternion q.
+func (q Quat) Mat4() Mat4 {
+	xx, yy, zz := q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+
+	return Mat4{
+		1 - 2*(yy+zz), 2 * (xy - wz), 2 * (xz + wy), 0,
+		2 * (xy + wz), 1 - 2*(xx+zz), 2 * (yz - wx), 0,
+		2 * (xz - wy), 2 * (yz + wx), 1 - 2*(xx+yy), 0,
+		0, 0, 0, 1,
+	}
+}
+
+// Slerp spherically interpolates between the unit quaternions q (t = 0)
+// and r (t = 1), taking the shortest path.
+func (q Quat) Slerp(r Quat, t float64) Quat {
+	d := q.Dot(r)
+	if d < 0 {
+		r = Quat{-r.W, -r.X, -r.Y, -r.Z}
+		d = -d
+	}
+
+	if d > 0.9995 {
+		return Quat{
+			q.W + (r.W-q.W)*t,
+			q.X + (r.X-q.X)*t,
+			q.Y + (r.Y-q.Y)*t,
+			q.Z + (r.Z-q.Z)*t,
+		}.Normalize()
+	}
+
+	theta := math.Acos(d)
+	sin := math.Sin(theta)
+	a := math.Sin((1-t)*theta) / sin
+	b := math.Sin(t*theta) / sin
+	return Quat{
+		q.W*a + r.W*b,
+		q.X*a + r.X*b,
+		q.Y*a + r.Y*b,
+		q.Z*a + r.Z*b,
+	}
+}
@@ -0,0 +1,72 @@
+package vecmath
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuatMatchesMatrix(t *testing.T) {
+	tests := []struct {
+		axis  Vec3
+		angle float64
+		m     Mat4
+	}{
+		{Vec3{X: 1}, 0.3, RotateX(0.3)},
+		{Vec3{Y: 1}, -1.1, RotateY(-1.1)},
+		{Vec3{Z: 1}, 2.5, RotateZ(2.5)},
+	}
+	points := []Vec3{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, -2, 3}}
+	for _, tt := range tests {
+		q := QuatFromAxisAngle(tt.axis, tt.angle)
+		if !nearMat4(q.Mat4(), tt.m) {
+			t.Errorf("quaternion of %v by %v has matrix %v, want %v", tt.axis, tt.angle, q.Mat4(), tt.m)
+		}
+		for _, p := range points {
+			if got, want := q.Rotate(p), tt.m.MulPoint(p); !nearVec3(got, want) {
+				t.Errorf("rotating %v by %v around %v gives %v, want %v", p, tt.angle, tt.axis, got, want)
+			}
+		}
+	}
+
+	q := QuatFromEuler(0.1, 0.2, 0.3)
+	want := RotateZ(0.3).Mul(RotateY(0.2)).Mul(RotateX(0.1))
+	if !nearMat4(q.Mat4(), want) {
+		t.Errorf("QuatFromEuler matrix = %v, want %v", q.Mat4(), want)
+	}
+}
+
+func TestQuatSlerp(t *testing.T) {
+	axis := Vec3{1, 1, 0}
+	a := QuatFromAxisAngle(axis, 0.2)
+	b := QuatFromAxisAngle(axis, 1.4)
+
+	for _, tt := range []float64{0, 0.25, 0.5, 1} {
+		got := a.Slerp(b, tt)
+		want := QuatFromAxisAngle(axis, 0.2+1.2*tt)
+		if !nearMat4(got.Mat4(), want.Mat4()) {
+			t.Errorf("Slerp(%v) = %v, want %v", tt, got, want)
+		}
+		if !near(got.Len(), 1) {
+			t.Errorf("Slerp(%v) has length %v", tt, got.Len())
+		}
+	}
+
+	// The negated quaternion is the same rotation; Slerp takes the short way.
+	neg := Quat{-b.W, -b.X, -b.Y, -b.Z}
+	if got := a.Slerp(neg, 0.5); !nearMat4(got.Mat4(), QuatFromAxisAngle(axis, 0.8).Mat4()) {
+		t.Errorf("Slerp toward the negated quaternion = %v", got)
+	}
+
+	// Nearly equal quaternions fall back to normalized lerp.
+	c := QuatFromAxisAngle(axis, 0.2+1e-6)
+	if got := a.Slerp(c, 0.5); !near(got.Len(), 1) || math.IsNaN(got.W) {
+		t.Errorf("Slerp of nearly equal quaternions = %v", got)
+	}
+}
+
+func TestQuatInverse(t *testing.T) {
+	q := QuatFromEuler(0.4, -0.7, 1.9)
+	if got := q.Mul(q.Inverse()); !near(got.W, 1) || !near(got.X, 0) || !near(got.Y, 0) || !near(got.Z, 0) {
+		t.Errorf("q * q⁻¹ = %v, want identity", got)
+	}
+}
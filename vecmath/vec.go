@@ -0,0 +1,189 @@
+// Package vecmath provides the vector, matrix and quaternion types used for
+// 2D and 3D graphics with quickcg.
+//
+// Matrices are stored row-major and multiply column vectors (M * v), so
+// transforms compose right to left: Translate(t).Mul(RotateY(a)) rotates first.
+// 3D helpers follow the OpenGL conventions: right-handed coordinates, a camera
+// looking down -Z and clip-space depth in [-1, 1].
+package vecmath
+
+import (
+	"math"
+)
+
+// Vec2 is a 2D vector.
+type Vec2 struct {
+	X, Y float64
+}
+
+// Vec3 is a 3D vector.
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+// Vec4 is a 4D vector, typically a homogeneous 3D point.
+type Vec4 struct {
+	X, Y, Z, W float64
+}
+
+// Add returns v + u.
+func (v Vec2) Add(u Vec2) Vec2 { return Vec2{v.X + u.X, v.Y + u.Y} }
+
+// Sub returns v - u.
+func (v Vec2) Sub(u Vec2) Vec2 { return Vec2{v.X - u.X, v.Y - u.Y} }
+
+// Scale returns v * s.
+func (v Vec2) Scale(s float64) Vec2 { return Vec2{v.X * s, v.Y * s} }
+
+// Mul returns the component-wise product of v and u.
+func (v Vec2) Mul(u Vec2) Vec2 { return Vec2{v.X * u.X, v.Y * u.Y} }
+
+// Neg returns -v.
+func (v Vec2) Neg() Vec2 { return Vec2{-v.X, -v.Y} }
+
+// Dot returns the dot product of v and u.
+func (v Vec2) Dot(u Vec2) float64 { return v.X*u.X + v.Y*u.Y }
+
+// Cross returns the z component of the 3D cross product of v and u,
+// which is positive when u lies counter-clockwise of v.
+func (v Vec2) Cross(u Vec2) float64 { return v.X*u.Y - v.Y*u.X }
+
+// Len returns the length of v.
+func (v Vec2) Len() float64 { return math.Hypot(v.X, v.Y) }
+
+// LenSq returns the squared length of v.
+func (v Vec2) LenSq() float64 { return v.Dot(v) }
+
+// Dist returns the distance between v and u.
+func (v Vec2) Dist(u Vec2) float64 { return v.Sub(u).Len() }
+
+// Normalize returns v scaled to unit length, or the zero vector if v is zero.
+func (v Vec2) Normalize() Vec2 {
+	l := v.Len()
+	if l == 0 {
+		return Vec2{}
+	}
+	return v.Scale(1 / l)
+}
+
+// Lerp linearly interpolates between v (t = 0) and u (t = 1).
+func (v Vec2) Lerp(u Vec2, t float64) Vec2 { return v.Add(u.Sub(v).Scale(t)) }
+
+// Perp returns v rotated by 90 degrees counter-clockwise.
+func (v Vec2) Perp() Vec2 { return Vec2{-v.Y, v.X} }
+
+// Rotate returns v rotated counter-clockwise by angle radians.
+func (v Vec2) Rotate(angle float64) Vec2 {
+	s, c := math.Sincos(angle)
+	return Vec2{v.X*c - v.Y*s, v.X*s + v.Y*c}
+}
+
+// Vec3 extends v with the given z component.
+func (v Vec2) Vec3(z float64) Vec3 { return Vec3{v.X, v.Y, z} }
+
+// Ints rounds v to integer pixel coordinates, as taken by quickcg drawing calls.
+func (v Vec2) Ints() (int, int) {
+	return int(math.Round(v.X)), int(math.Round(v.Y))
+}
+
+// Add returns v + u.
+func (v Vec3) Add(u Vec3) Vec3 { return Vec3{v.X + u.X, v.Y + u.Y, v.Z + u.Z} }
+
+// Sub returns v - u.
+func (v Vec3) Sub(u Vec3) Vec3 { return Vec3{v.X - u.X, v.Y - u.Y, v.Z - u.Z} }
+
+// Scale returns v * s.
+func (v Vec3) Scale(s float64) Vec3 { return Vec3{v.X * s, v.Y * s, v.Z * s} }
+
+// Mul returns the component-wise product of v and u.
+func (v Vec3) Mul(u Vec3) Vec3 { return Vec3{v.X * u.X, v.Y * u.Y, v.Z * u.Z} }
+
+// Neg returns -v.
+func (v Vec3) Neg() Vec3 { return Vec3{-v.X, -v.Y, -v.Z} }
+
+// Dot returns the dot product of v and u.
+func (v Vec3) Dot(u Vec3) float64 { return v.X*u.X + v.Y*u.Y + v.Z*u.Z }
+
+// Cross returns the cross product of v and u.
+func (v Vec3) Cross(u Vec3) Vec3 {
+	return Vec3{
+		v.Y*u.Z - v.Z*u.Y,
+		v.Z*u.X - v.X*u.Z,
+		v.X*u.Y - v.Y*u.X,
+	}
+}
+
+// Len returns the length of v.
+func (v Vec3) Len() float64 { return math.Sqrt(v.Dot(v)) }
+
+// LenSq returns the squared length of v.
+func (v Vec3) LenSq() float64 { return v.Dot(v) }
+
+// Dist returns the distance between v and u.
+func (v Vec3) Dist(u Vec3) float64 { return v.Sub(u).Len() }
+
+// Normalize returns v scaled to unit length, or the zero vector if v is zero.
+func (v Vec3) Normalize() Vec3 {
+	l := v.Len()
+	if l == 0 {
+		return Vec3{}
+	}
+	return v.Scale(1 / l)
+}
+
+// Lerp linearly interpolates between v (t = 0) and u (t = 1).
+func (v Vec3) Lerp(u Vec3, t float64) Vec3 { return v.Add(u.Sub(v).Scale(t)) }
+
+// Reflect reflects v about the plane with the unit normal n.
+func (v Vec3) Reflect(n Vec3) Vec3 { return v.Sub(n.Scale(2 * v.Dot(n))) }
+
+// Vec2 drops the z component of v.
+func (v Vec3) Vec2() Vec2 { return Vec2{v.X, v.Y} }
+
+// Vec4 extends v with the given w component:
+// 1 for points, 0 for directions.
+func (v Vec3) Vec4(w float64) Vec4 { return Vec4{v.X, v.Y, v.Z, w} }
+
+// Add returns v + u.
+func (v Vec4) Add(u Vec4) Vec4 { return Vec4{v.X + u.X, v.Y + u.Y, v.Z + u.Z, v.W + u.W} }
+
+// Sub returns v - u.
+func (v Vec4) Sub(u Vec4) Vec4 { return Vec4{v.X - u.X, v.Y - u.Y, v.Z - u.Z, v.W - u.W} }
+
+// Scale returns v * s.
+func (v Vec4) Scale(s float64) Vec4 { return Vec4{v.X * s, v.Y * s, v.Z * s, v.W * s} }
+
+// Mul returns the component-wise product of v and u.
+func (v Vec4) Mul(u Vec4) Vec4 { return Vec4{v.X * u.X, v.Y * u.Y, v.Z * u.Z, v.W * u.W} }
+
+// Neg returns -v.
+func (v Vec4) Neg() Vec4 { return Vec4{-v.X, -v.Y, -v.Z, -v.W} }
+
+// Dot returns the dot product of v and u.
+func (v Vec4) Dot(u Vec4) float64 { return v.X*u.X + v.Y*u.Y + v.Z*u.Z + v.W*u.W }
+
+// Len returns the length of v.
+func (v Vec4) Len() float64 { return math.Sqrt(v.Dot(v)) }
+
+// LenSq returns the squared length of v.
+func (v Vec4) LenSq() float64 { return v.Dot(v) }
+
+// Normalize returns v scaled to unit length, or the zero vector if v is zero.
+func (v Vec4) Normalize() Vec4 {
+	l := v.Len()
+	if l == 0 {
+		return Vec4{}
+	}
+	return v.Scale(1 / l)
+}
+
+// Lerp linearly interpolates between v (t = 0) and u (t = 1).
+func (v Vec4) Lerp(u Vec4, t float64) Vec4 { return v.Add(u.Sub(v).Scale(t)) }
+
+// Vec3 drops the w component of v.
+func (v Vec4) Vec3() Vec3 { return Vec3{v.X, v.Y, v.Z} }
+
+// PerspectiveDivide returns the 3D point represented by the homogeneous vector v.
+func (v Vec4) PerspectiveDivide() Vec3 {
+	return Vec3{v.X / v.W, v.Y / v.W, v.Z / v.W}
+}